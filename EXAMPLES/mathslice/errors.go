@@ -0,0 +1,109 @@
+package mathslice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMode — поведение MapSliceErr и FoldSliceErr при ошибке в пользовательской функции
+type ErrMode int
+
+const (
+	// StopOnFirst — остановиться на первой ошибке
+	StopOnFirst ErrMode = iota
+	// CollectAll — обработать все элементы и вернуть все ошибки разом
+	CollectAll
+)
+
+// IndexError — ошибка обработки элемента с индексом Index. Исходная ошибка доступна через errors.Is и errors.As
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("mathslice: element %d: %v", e.Index, e.Err)
+}
+
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+// SliceError — все ошибки, собранные в режиме CollectAll, в порядке индексов
+type SliceError []error
+
+func (errs SliceError) Error() string {
+	out := make([]string, len(errs))
+	for i, err := range errs {
+		out[i] = err.Error()
+	}
+	return strings.Join(out, "; ")
+}
+
+// Is — сообщает, подходит ли под target хотя бы одна из ошибок
+func (errs SliceError) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As — находит первую ошибку, которую можно привести к target
+func (errs SliceError) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// MapSliceErr — Map для функции, которая может вернуть ошибку. Каждая ошибка оборачивается в *IndexError.
+// В режиме StopOnFirst возвращает nil и первую ошибку.
+// В режиме CollectAll обрабатывает весь слайс и возвращает результат, в котором
+// на местах неудачных элементов стоят нулевые значения, и SliceError со всеми ошибками
+func MapSliceErr[T, U any](s []T, op func(T) (U, error), mode ErrMode) ([]U, error) {
+	var errs SliceError
+	res := make([]U, len(s))
+	for i, v := range s {
+		u, err := op(v)
+		if err != nil {
+			if mode == StopOnFirst {
+				return nil, &IndexError{Index: i, Err: err}
+			}
+			errs = append(errs, &IndexError{Index: i, Err: err})
+			continue
+		}
+		res[i] = u
+	}
+	if len(errs) != 0 {
+		return res, errs
+	}
+	return res, nil
+}
+
+// FoldSliceErr — FoldSlice для функции, которая может вернуть ошибку.
+// В режиме StopOnFirst возвращает аккумулятор на момент ошибки и первую ошибку.
+// В режиме CollectAll элементы с ошибкой пропускаются, аккумулятор для них не меняется
+func FoldSliceErr[T, A any](s []T, op func(A, T) (A, error), init A, mode ErrMode) (A, error) {
+	var errs SliceError
+	res := init
+	for i, v := range s {
+		next, err := op(res, v)
+		if err != nil {
+			if mode == StopOnFirst {
+				return res, &IndexError{Index: i, Err: err}
+			}
+			errs = append(errs, &IndexError{Index: i, Err: err})
+			continue
+		}
+		res = next
+	}
+	if len(errs) != 0 {
+		return res, errs
+	}
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package mathslice
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestMapSliceErr(t *testing.T) {
+	in := []string{"1", "x", "3", "y"}
+
+	res, err := MapSliceErr(in, strconv.Atoi, StopOnFirst)
+	if res != nil {
+		t.Errorf("expected nil result; got: %v", res)
+	}
+	var ie *IndexError
+	if !errors.As(err, &ie) || ie.Index != 1 {
+		t.Fatalf("expected *IndexError at index 1; got: %v", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected error to wrap strconv.ErrSyntax: %v", err)
+	}
+	var ne *strconv.NumError
+	if !errors.As(err, &ne) || ne.Num != "x" {
+		t.Errorf("expected *strconv.NumError for x; got: %v", err)
+	}
+
+	res, err = MapSliceErr(in, strconv.Atoi, CollectAll)
+	if expected := []int{1, 0, 3, 0}; !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected %v; got: %v", expected, res)
+	}
+	var errs SliceError
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected SliceError with 2 errors; got: %v", err)
+	}
+	if !errors.As(errs[1], &ie) || ie.Index != 3 {
+		t.Errorf("expected second error at index 3; got: %v", errs[1])
+	}
+	if !errors.Is(err, strconv.ErrSyntax) || !errors.As(err, &ne) {
+		t.Errorf("expected collected errors to unwrap: %v", err)
+	}
+
+	if res, err := MapSliceErr([]string{"7"}, strconv.Atoi, CollectAll); err != nil || res[0] != 7 {
+		t.Errorf("expected [7] and nil; got: %v, %v", res, err)
+	}
+}
+
+func TestFoldSliceErr(t *testing.T) {
+	errNegative := errors.New("negative")
+	add := func(acc int, v int) (int, error) {
+		if v < 0 {
+			return 0, errNegative
+		}
+		return acc + v, nil
+	}
+	s := []int{1, 2, -1, 4, -2}
+
+	got, err := FoldSliceErr(s, add, 0, StopOnFirst)
+	var ie *IndexError
+	if got != 3 || !errors.As(err, &ie) || ie.Index != 2 || !errors.Is(err, errNegative) {
+		t.Errorf("expected 3 and error at index 2; got: %d, %v", got, err)
+	}
+
+	got, err = FoldSliceErr(s, add, 0, CollectAll)
+	if got != 7 {
+		t.Errorf("expected 7; got: %d", got)
+	}
+	if err == nil || err.Error() != "mathslice: element 2: negative; mathslice: element 4: negative" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
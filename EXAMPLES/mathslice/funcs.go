@@ -0,0 +1,114 @@
+package mathslice
+
+// Pair — пара значений, которую возвращает Zip
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Map — возвращает новый слайс из результатов f для каждого элемента s.
+// В отличие от MapSlice исходный слайс не меняется
+func Map[T, U any](s []T, f func(T) U) []U {
+	res := make([]U, len(s))
+	for i, v := range s {
+		res[i] = f(v)
+	}
+	return res
+}
+
+// Filter — возвращает новый слайс из элементов, для которых pred вернул true
+func Filter[T any](s []T, pred func(T) bool) []T {
+	var res []T
+	for _, v := range s {
+		if pred(v) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Reduce — свёртка без начального значения: первым аккумулятором служит s[0].
+// Для пустого слайса возвращает нулевое значение и false
+func Reduce[T any](s []T, op func(T, T) T) (res T, ok bool) {
+	if len(s) == 0 {
+		return res, false
+	}
+	return FoldSlice(s[1:], op, s[0]), true
+}
+
+// FlatMap — применяет f к каждому элементу и склеивает полученные слайсы
+func FlatMap[T, U any](s []T, f func(T) []U) []U {
+	var res []U
+	for _, v := range s {
+		res = append(res, f(v)...)
+	}
+	return res
+}
+
+// Zip — объединяет два слайса попарно. Длина результата равна длине более короткого слайса
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	length := len(a)
+	if len(b) < length {
+		length = len(b)
+	}
+	res := make([]Pair[A, B], length)
+	for i := 0; i < length; i++ {
+		res[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return res
+}
+
+// Chunk — делит слайс на последовательные куски длины size; последний кусок может быть короче.
+// Куски ссылаются на исходный массив, поэтому их ёмкость ограничена их длиной
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic("mathslice: chunk size must be positive")
+	}
+	res := make([][]T, 0, (len(s)+size-1)/size)
+	for start := 0; start < len(s); start += size {
+		end := start + size
+		if end > len(s) {
+			end = len(s)
+		}
+		res = append(res, s[start:end:end])
+	}
+	return res
+}
+
+// Window — возвращает все скользящие окна длины size с шагом 1.
+// Если слайс короче окна, результат пустой
+func Window[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic("mathslice: window size must be positive")
+	}
+	if len(s) < size {
+		return nil
+	}
+	res := make([][]T, 0, len(s)-size+1)
+	for start := 0; start+size <= len(s); start++ {
+		res = append(res, s[start:start+size:start+size])
+	}
+	return res
+}
+
+// GroupBy — раскладывает элементы по ключу, сохраняя их порядок внутри группы
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	res := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		res[k] = append(res[k], v)
+	}
+	return res
+}
+
+// Partition — делит слайс на две части: элементы, для которых pred вернул true, и все остальные
+func Partition[T any](s []T, pred func(T) bool) (yes, no []T) {
+	for _, v := range s {
+		if pred(v) {
+			yes = append(yes, v)
+		} else {
+			no = append(no, v)
+		}
+	}
+	return yes, no
+}
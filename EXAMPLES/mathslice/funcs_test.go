@@ -0,0 +1,81 @@
+package mathslice
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSliceCompat(t *testing.T) {
+	s := Slice{1, 2, 3}
+	MapSlice(s, func(i Element) Element { return i * 2 })
+	if expected := (Slice{2, 4, 6}); !reflect.DeepEqual(s, expected) {
+		t.Errorf("expected %v; got: %v", expected, s)
+	}
+	if sum := SumSlice(s); sum != 12 {
+		t.Errorf("expected sum 12; got: %d", sum)
+	}
+	mul := FoldSlice(s, func(x, y Element) Element { return x * y }, 1)
+	if mul != 48 {
+		t.Errorf("expected product 48; got: %d", mul)
+	}
+}
+
+func TestMapDoesNotMutate(t *testing.T) {
+	s := []int{1, 2, 3}
+	got := Map(s, strconv.Itoa)
+	if expected := []string{"1", "2", "3"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v; got: %v", expected, got)
+	}
+	if expected := []int{1, 2, 3}; !reflect.DeepEqual(s, expected) {
+		t.Errorf("source changed: %v", s)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	even := func(v int) bool { return v%2 == 0 }
+
+	if got := Filter(s, even); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("Filter: got %v", got)
+	}
+	if got, ok := Reduce(s, func(a, b int) int { return a - b }); !ok || got != -13 {
+		t.Errorf("Reduce: got %d, %v", got, ok)
+	}
+	if _, ok := Reduce([]int{}, func(a, b int) int { return a + b }); ok {
+		t.Error("Reduce: expected false for empty slice")
+	}
+	if got := FlatMap(s[:3], func(v int) []int { return []int{v, v} }); !reflect.DeepEqual(got, []int{1, 1, 2, 2, 3, 3}) {
+		t.Errorf("FlatMap: got %v", got)
+	}
+	zipped := Zip(s, []string{"a", "b"})
+	if expected := []Pair[int, string]{{1, "a"}, {2, "b"}}; !reflect.DeepEqual(zipped, expected) {
+		t.Errorf("Zip: expected %v; got: %v", expected, zipped)
+	}
+	if got := Chunk(s, 2); !reflect.DeepEqual(got, [][]int{{1, 2}, {3, 4}, {5}}) {
+		t.Errorf("Chunk: got %v", got)
+	}
+	if got := Window(s, 3); !reflect.DeepEqual(got, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}) {
+		t.Errorf("Window: got %v", got)
+	}
+	if got := Window(s, 6); len(got) != 0 {
+		t.Errorf("Window: expected no windows; got %v", got)
+	}
+	groups := GroupBy(s, func(v int) bool { return even(v) })
+	if !reflect.DeepEqual(groups[true], []int{2, 4}) || !reflect.DeepEqual(groups[false], []int{1, 3, 5}) {
+		t.Errorf("GroupBy: got %v", groups)
+	}
+	yes, no := Partition(s, even)
+	if !reflect.DeepEqual(yes, []int{2, 4}) || !reflect.DeepEqual(no, []int{1, 3, 5}) {
+		t.Errorf("Partition: got %v / %v", yes, no)
+	}
+}
+
+func TestChunkIsolation(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	chunks := Chunk(s, 2)
+	chunks[0] = append(chunks[0], 100)
+	if s[2] != 3 {
+		t.Errorf("append to chunk overwrote source: %v", s)
+	}
+}
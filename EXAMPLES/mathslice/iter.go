@@ -0,0 +1,125 @@
+package mathslice
+
+// Iter — ленивый итератор. Каждый вызов возвращает следующий элемент и true,
+// а когда элементы закончились — нулевое значение и false.
+// Адаптеры ниже не создают промежуточных слайсов: значения вычисляются по одному
+// только тогда, когда их запрашивает Collect, FoldIter или другой потребитель
+type Iter[T any] func() (T, bool)
+
+// Iterate — итератор по элементам слайса
+func Iterate[T any](s []T) Iter[T] {
+	i := 0
+	return func() (v T, ok bool) {
+		if i >= len(s) {
+			return v, false
+		}
+		v = s[i]
+		i++
+		return v, true
+	}
+}
+
+// MapIter — лениво применяет f к каждому элементу итератора
+func MapIter[T, U any](it Iter[T], f func(T) U) Iter[U] {
+	return func() (u U, ok bool) {
+		v, ok := it()
+		if !ok {
+			return u, false
+		}
+		return f(v), true
+	}
+}
+
+// FlatMapIter — лениво разворачивает слайсы, которые возвращает f
+func FlatMapIter[T, U any](it Iter[T], f func(T) []U) Iter[U] {
+	var (
+		buf []U
+		pos int
+	)
+	return func() (u U, ok bool) {
+		for pos >= len(buf) {
+			v, ok := it()
+			if !ok {
+				return u, false
+			}
+			buf, pos = f(v), 0
+		}
+		u = buf[pos]
+		pos++
+		return u, true
+	}
+}
+
+// ZipIter — лениво объединяет два итератора попарно, пока не закончится любой из них
+func ZipIter[A, B any](a Iter[A], b Iter[B]) Iter[Pair[A, B]] {
+	return func() (p Pair[A, B], ok bool) {
+		first, ok := a()
+		if !ok {
+			return p, false
+		}
+		second, ok := b()
+		if !ok {
+			return p, false
+		}
+		return Pair[A, B]{First: first, Second: second}, true
+	}
+}
+
+// Filter — пропускает только элементы, для которых pred вернул true
+func (it Iter[T]) Filter(pred func(T) bool) Iter[T] {
+	return func() (v T, ok bool) {
+		for {
+			v, ok = it()
+			if !ok || pred(v) {
+				return v, ok
+			}
+		}
+	}
+}
+
+// Take — ограничивает итератор первыми n элементами
+func (it Iter[T]) Take(n int) Iter[T] {
+	return func() (v T, ok bool) {
+		if n <= 0 {
+			return v, false
+		}
+		n--
+		return it()
+	}
+}
+
+// ChunkIter — лениво группирует элементы в куски длины size; последний кусок может быть короче
+func ChunkIter[T any](it Iter[T], size int) Iter[[]T] {
+	if size <= 0 {
+		panic("mathslice: chunk size must be positive")
+	}
+	return func() ([]T, bool) {
+		var chunk []T
+		for len(chunk) < size {
+			v, ok := it()
+			if !ok {
+				break
+			}
+			chunk = append(chunk, v)
+		}
+		return chunk, len(chunk) > 0
+	}
+}
+
+// Collect — вычисляет итератор до конца и собирает элементы в слайс
+func (it Iter[T]) Collect() []T {
+	var res []T
+	for v, ok := it(); ok; v, ok = it() {
+		res = append(res, v)
+	}
+	return res
+}
+
+// FoldIter — свёртка итератора слева, аналог FoldSlice
+func FoldIter[T, A any](it Iter[T], op func(A, T) A, init A) A {
+	res := init
+	for v, ok := it(); ok; v, ok = it() {
+		res = op(res, v)
+	}
+	return res
+}
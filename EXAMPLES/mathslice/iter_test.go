@@ -0,0 +1,48 @@
+package mathslice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIterPipeline(t *testing.T) {
+	calls := 0
+	it := MapIter(Iterate([]int{1, 2, 3, 4, 5, 6}), func(v int) int {
+		calls++
+		return v * v
+	}).Filter(func(v int) bool { return v%2 == 0 }).Take(2)
+
+	if calls != 0 {
+		t.Fatalf("pipeline must be lazy; map called %d times before Collect", calls)
+	}
+	if got := it.Collect(); !reflect.DeepEqual(got, []int{4, 16}) {
+		t.Errorf("expected [4 16]; got: %v", got)
+	}
+	if calls != 4 {
+		t.Errorf("expected 4 map calls; got: %d", calls)
+	}
+}
+
+func TestIterAdapters(t *testing.T) {
+	flat := FlatMapIter(Iterate([]int{0, 2, 1}), func(n int) []int {
+		return make([]int, n)
+	}).Collect()
+	if len(flat) != 3 {
+		t.Errorf("FlatMapIter: expected 3 elements; got: %v", flat)
+	}
+
+	zipped := ZipIter(Iterate([]string{"a", "b", "c"}), Iterate([]int{1, 2})).Collect()
+	if expected := []Pair[string, int]{{"a", 1}, {"b", 2}}; !reflect.DeepEqual(zipped, expected) {
+		t.Errorf("ZipIter: expected %v; got: %v", expected, zipped)
+	}
+
+	chunks := ChunkIter(Iterate([]int{1, 2, 3, 4, 5}), 2).Collect()
+	if expected := [][]int{{1, 2}, {3, 4}, {5}}; !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("ChunkIter: expected %v; got: %v", expected, chunks)
+	}
+
+	sum := FoldIter(Iterate(Slice{1, 2, 3}), func(acc Element, v Element) Element { return acc + v }, 0)
+	if sum != 6 {
+		t.Errorf("FoldIter: expected 6; got: %d", sum)
+	}
+}
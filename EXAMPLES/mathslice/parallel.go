@@ -0,0 +1,134 @@
+package mathslice
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError — паника в пользовательской функции, перехваченная параллельным обходом
+type PanicError struct {
+	// Index — индекс элемента, на котором случилась паника;
+	// -1, если паника произошла при объединении частичных результатов ParallelFold
+	Index int
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("mathslice: callback panicked at index %d: %v", e.Index, e.Value)
+}
+
+// ParallelMap — параллельный аналог Map. Слайс делится на parallelism непрерывных частей,
+// каждая обрабатывается в своей горутине; порядок результатов совпадает с порядком s.
+// Если parallelism <= 0, используется runtime.GOMAXPROCS(0).
+// При отмене ctx или панике в f возвращает ошибку и nil вместо результата
+func ParallelMap[T, U any](ctx context.Context, s []T, f func(T) U, parallelism int) ([]U, error) {
+	res := make([]U, len(s))
+	err := forEachChunk(ctx, len(s), parallelism, func(_, _, i int) {
+		res[i] = f(s[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ParallelFold — параллельная свёртка. Каждая часть слайса сворачивается отдельно,
+// затем частичные результаты по порядку сворачиваются с init.
+// Для ассоциативной op результат в точности совпадает с FoldSlice(s, op, init)
+func ParallelFold[T any](ctx context.Context, s []T, op func(T, T) T, init T, parallelism int) (T, error) {
+	partial := make([]T, chunkCount(len(s), parallelism))
+	err := forEachChunk(ctx, len(s), parallelism, func(c, lo, i int) {
+		if i == lo {
+			partial[c] = s[i]
+			return
+		}
+		partial[c] = op(partial[c], s[i])
+	})
+	if err != nil {
+		return init, err
+	}
+	return combine(partial, op, init)
+}
+
+// combine — сворачивает частичные результаты, превращая панику в ошибку
+func combine[T any](partial []T, op func(T, T) T, init T) (res T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			res, err = init, &PanicError{Index: -1, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return FoldSlice(partial, op, init), nil
+}
+
+// chunkCount — число частей, на которые делится слайс длины n
+func chunkCount(n, parallelism int) int {
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	if parallelism > n {
+		parallelism = n
+	}
+	return parallelism
+}
+
+// forEachChunk — делит диапазон [0, n) на непрерывные части и вызывает step(chunk, lo, i)
+// для каждого индекса в отдельной горутине на часть. Возвращает первую ошибку:
+// отмену контекста или перехваченную панику; после неё остальные горутины останавливаются
+func forEachChunk(ctx context.Context, n, parallelism int, step func(c, lo, i int)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	chunks := chunkCount(n, parallelism)
+	if chunks == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	setErr := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	size, rest := n/chunks, n%chunks
+	lo := 0
+	for c := 0; c < chunks; c++ {
+		hi := lo + size
+		// первые rest частей на один элемент длиннее, чтобы покрыть весь диапазон
+		if c < rest {
+			hi++
+		}
+		wg.Add(1)
+		go func(c, lo, hi int) {
+			defer wg.Done()
+			i := lo
+			defer func() {
+				if r := recover(); r != nil {
+					setErr(&PanicError{Index: i, Value: r, Stack: debug.Stack()})
+				}
+			}()
+			for ; i < hi; i++ {
+				if err := ctx.Err(); err != nil {
+					setErr(err)
+					return
+				}
+				step(c, lo, i)
+			}
+		}(c, lo, hi)
+		lo = hi
+	}
+	wg.Wait()
+	return firstErr
+}
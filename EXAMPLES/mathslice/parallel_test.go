@@ -0,0 +1,91 @@
+package mathslice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParallelMapOrder(t *testing.T) {
+	s := make([]int, 1001)
+	for i := range s {
+		s[i] = i
+	}
+	for _, p := range []int{0, 1, 3, 8, 2000} {
+		got, err := ParallelMap(context.Background(), s, func(v int) int { return v * 2 }, p)
+		if err != nil {
+			t.Fatalf("parallelism %d: unexpected error: %v", p, err)
+		}
+		if expected := Map(s, func(v int) int { return v * 2 }); !reflect.DeepEqual(got, expected) {
+			t.Errorf("parallelism %d: order is not preserved", p)
+		}
+	}
+}
+
+func TestParallelFoldMatchesSerial(t *testing.T) {
+	s := make(Slice, 777)
+	for i := range s {
+		s[i] = Element(i%13 - 6)
+	}
+	add := func(x, y Element) Element { return x + y }
+	expected := FoldSlice(s, add, 10)
+	for _, p := range []int{0, 1, 2, 5, 16, 1000} {
+		got, err := ParallelFold(context.Background(), s, add, 10, p)
+		if err != nil {
+			t.Fatalf("parallelism %d: unexpected error: %v", p, err)
+		}
+		if got != expected {
+			t.Errorf("parallelism %d: expected %d; got: %d", p, expected, got)
+		}
+	}
+
+	// конкатенация ассоциативна, но не коммутативна — проверяем порядок объединения
+	words := []string{"a", "b", "c", "d", "e", "f", "g"}
+	concat := func(x, y string) string { return x + y }
+	got, err := ParallelFold(context.Background(), words, concat, ">", 3)
+	if err != nil || got != ">abcdefg" {
+		t.Errorf("expected >abcdefg; got: %q, %v", got, err)
+	}
+
+	if got, err := ParallelFold(context.Background(), Slice{}, add, 7, 4); err != nil || got != 7 {
+		t.Errorf("empty slice: expected init 7; got: %d, %v", got, err)
+	}
+}
+
+func TestParallelPanic(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6}
+	_, err := ParallelMap(context.Background(), s, func(v int) int {
+		if v == 4 {
+			panic("boom")
+		}
+		return v
+	}, 2)
+
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *PanicError; got: %v", err)
+	}
+	if pe.Index != 3 || pe.Value != "boom" {
+		t.Errorf("expected panic at index 3 with boom; got: %d, %v", pe.Index, pe.Value)
+	}
+}
+
+func TestParallelCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ParallelMap(ctx, []int{1, 2}, func(v int) int { return v }, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled; got: %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	s := make([]int, 1000)
+	_, err := ParallelFold(ctx, s, func(x, y int) int {
+		cancel()
+		return x + y
+	}, 0, 4)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled; got: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+// Package mathslice — функции высшего порядка для работы со слайсами.
+package mathslice
+
+// Element — тип элемента слайса
+type Element int
+
+// Slice — слайс элементов
+type Slice []Element
+
+// Number — ограничение для числовых типов, которые можно складывать и умножать
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SumSlice — возвращает сумму элементов слайса
+func SumSlice[T Number](s []T) (res T) {
+	for _, v := range s {
+		res += v
+	}
+	return
+}
+
+// MapSlice — применяет op к каждому элементу слайса.
+// Слайс изменяется на месте; если нужен новый слайс, используйте Map
+func MapSlice[T any](s []T, op func(T) T) {
+	for i, v := range s {
+		s[i] = op(v)
+	}
+}
+
+// FoldSlice — свёртка слайса слева: op(...op(op(init, s[0]), s[1])..., s[n-1])
+func FoldSlice[T, A any](s []T, op func(A, T) A, init A) A {
+	res := init
+	for _, v := range s {
+		res = op(res, v)
+	}
+	return res
+}
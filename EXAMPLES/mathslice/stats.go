@@ -0,0 +1,208 @@
+package mathslice
+
+// Агрегаты по скользящему окну. Каждый агрегат есть в двух видах:
+// потоковый тип, который принимает значения по одному через Push,
+// и функция, которая прогоняет через него весь слайс.
+// Функции по окну возвращают по значению на каждое полное окно,
+// то есть len(s)-window+1 значений, как и Window
+
+// RollingWindow — потоковая сумма и среднее по последним size значениям
+type RollingWindow[T Number] struct {
+	buf   []T
+	pos   int
+	count int
+	sum   T
+}
+
+// NewRollingWindow — создаёт окно длины size
+func NewRollingWindow[T Number](size int) *RollingWindow[T] {
+	if size <= 0 {
+		panic("mathslice: window size must be positive")
+	}
+	return &RollingWindow[T]{buf: make([]T, size)}
+}
+
+// Push — добавляет значение, вытесняя самое старое, если окно заполнено.
+// Возвращает true, когда окно заполнено
+func (w *RollingWindow[T]) Push(v T) bool {
+	if w.count == len(w.buf) {
+		w.sum -= w.buf[w.pos]
+	} else {
+		w.count++
+	}
+	w.buf[w.pos] = v
+	w.sum += v
+	w.pos = (w.pos + 1) % len(w.buf)
+	return w.Full()
+}
+
+// Full — сообщает, заполнено ли окно
+func (w *RollingWindow[T]) Full() bool {
+	return w.count == len(w.buf)
+}
+
+// Sum — сумма значений в окне
+func (w *RollingWindow[T]) Sum() T {
+	return w.sum
+}
+
+// Mean — среднее значений в окне; до первого значения возвращает 0
+func (w *RollingWindow[T]) Mean() float64 {
+	if w.count == 0 {
+		return 0
+	}
+	return float64(w.sum) / float64(w.count)
+}
+
+// ExtremumWindow — потоковый минимум или максимум по окну.
+// Монотонная очередь хранит только кандидатов, поэтому каждое значение
+// добавляется и удаляется не больше одного раза: O(1) амортизированно на Push
+type ExtremumWindow[T Number] struct {
+	size   int
+	next   int
+	deque  []extremumEntry[T]
+	better func(a, b T) bool
+}
+
+type extremumEntry[T Number] struct {
+	index int
+	value T
+}
+
+// NewMinWindow — создаёт потоковый минимум по окну длины size
+func NewMinWindow[T Number](size int) *ExtremumWindow[T] {
+	return newExtremumWindow(size, func(a, b T) bool { return a <= b })
+}
+
+// NewMaxWindow — создаёт потоковый максимум по окну длины size
+func NewMaxWindow[T Number](size int) *ExtremumWindow[T] {
+	return newExtremumWindow(size, func(a, b T) bool { return a >= b })
+}
+
+func newExtremumWindow[T Number](size int, better func(a, b T) bool) *ExtremumWindow[T] {
+	if size <= 0 {
+		panic("mathslice: window size must be positive")
+	}
+	return &ExtremumWindow[T]{size: size, better: better}
+}
+
+// Push — добавляет значение и возвращает экстремум окна.
+// Пока окно не заполнено, второй результат — false
+func (w *ExtremumWindow[T]) Push(v T) (T, bool) {
+	// кандидаты, которые хуже нового значения, уже никогда не станут экстремумом
+	for len(w.deque) > 0 && w.better(v, w.deque[len(w.deque)-1].value) {
+		w.deque = w.deque[:len(w.deque)-1]
+	}
+	w.deque = append(w.deque, extremumEntry[T]{index: w.next, value: v})
+	w.next++
+	if w.deque[0].index <= w.next-1-w.size {
+		w.deque = w.deque[1:]
+	}
+	return w.deque[0].value, w.next >= w.size
+}
+
+// EMA — экспоненциальное скользящее среднее с коэффициентом сглаживания alpha из (0, 1].
+// Первое значение становится начальным средним
+type EMA struct {
+	alpha   float64
+	value   float64
+	started bool
+}
+
+// NewEMA — создаёт EMA с коэффициентом alpha
+func NewEMA(alpha float64) *EMA {
+	if alpha <= 0 || alpha > 1 {
+		panic("mathslice: EMA alpha must be in (0, 1]")
+	}
+	return &EMA{alpha: alpha}
+}
+
+// Push — добавляет значение и возвращает новое среднее
+func (e *EMA) Push(v float64) float64 {
+	if !e.started {
+		e.value, e.started = v, true
+		return e.value
+	}
+	e.value += e.alpha * (v - e.value)
+	return e.value
+}
+
+// Value — текущее среднее
+func (e *EMA) Value() float64 {
+	return e.value
+}
+
+// CumulativeSum — потоковая накопленная сумма; нулевое значение готово к работе
+type CumulativeSum[T Number] struct {
+	sum T
+}
+
+// Push — добавляет значение и возвращает сумму всех значений на текущий момент
+func (c *CumulativeSum[T]) Push(v T) T {
+	c.sum += v
+	return c.sum
+}
+
+// RollingSum — суммы всех полных окон длины window
+func RollingSum[T Number](s []T, window int) []T {
+	w := NewRollingWindow[T](window)
+	var res []T
+	for _, v := range s {
+		if w.Push(v) {
+			res = append(res, w.Sum())
+		}
+	}
+	return res
+}
+
+// RollingMean — средние всех полных окон длины window
+func RollingMean[T Number](s []T, window int) []float64 {
+	w := NewRollingWindow[T](window)
+	var res []float64
+	for _, v := range s {
+		if w.Push(v) {
+			res = append(res, w.Mean())
+		}
+	}
+	return res
+}
+
+// RollingMin — минимумы всех полных окон длины window за O(n)
+func RollingMin[T Number](s []T, window int) []T {
+	return rollingExtremum(s, NewMinWindow[T](window))
+}
+
+// RollingMax — максимумы всех полных окон длины window за O(n)
+func RollingMax[T Number](s []T, window int) []T {
+	return rollingExtremum(s, NewMaxWindow[T](window))
+}
+
+func rollingExtremum[T Number](s []T, w *ExtremumWindow[T]) []T {
+	var res []T
+	for _, v := range s {
+		if m, ok := w.Push(v); ok {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// ExpMovingAverage — EMA для каждого префикса слайса; длина результата равна длине s
+func ExpMovingAverage[T Number](s []T, alpha float64) []float64 {
+	e := NewEMA(alpha)
+	res := make([]float64, len(s))
+	for i, v := range s {
+		res[i] = e.Push(float64(v))
+	}
+	return res
+}
+
+// CumSum — накопленные суммы: res[i] = s[0] + ... + s[i]
+func CumSum[T Number](s []T) []T {
+	var c CumulativeSum[T]
+	res := make([]T, len(s))
+	for i, v := range s {
+		res[i] = c.Push(v)
+	}
+	return res
+}
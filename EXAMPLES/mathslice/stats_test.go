@@ -0,0 +1,106 @@
+package mathslice
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestRollingAgainstWindow(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := make(Slice, 200)
+	for i := range s {
+		s[i] = Element(rnd.Intn(100) - 50)
+	}
+	minOf := func(w Slice) Element {
+		res, _ := Reduce(w, func(a, b Element) Element {
+			if b < a {
+				return b
+			}
+			return a
+		})
+		return res
+	}
+	maxOf := func(w Slice) Element {
+		res, _ := Reduce(w, func(a, b Element) Element {
+			if b > a {
+				return b
+			}
+			return a
+		})
+		return res
+	}
+
+	for _, size := range []int{1, 2, 7, 200} {
+		windows := Window(s, size)
+		var sums, mins, maxs Slice
+		var means []float64
+		for _, w := range windows {
+			sums = append(sums, SumSlice(w))
+			means = append(means, float64(SumSlice(w))/float64(size))
+			mins = append(mins, minOf(w))
+			maxs = append(maxs, maxOf(w))
+		}
+		if got := RollingSum(s, size); !reflect.DeepEqual(Slice(got), sums) {
+			t.Errorf("window %d: RollingSum mismatch", size)
+		}
+		if got := RollingMean(s, size); !reflect.DeepEqual(got, means) {
+			t.Errorf("window %d: RollingMean mismatch", size)
+		}
+		if got := RollingMin(s, size); !reflect.DeepEqual(Slice(got), mins) {
+			t.Errorf("window %d: RollingMin mismatch", size)
+		}
+		if got := RollingMax(s, size); !reflect.DeepEqual(Slice(got), maxs) {
+			t.Errorf("window %d: RollingMax mismatch", size)
+		}
+	}
+
+	if got := RollingMax(s, 201); len(got) != 0 {
+		t.Errorf("expected no windows; got: %v", got)
+	}
+}
+
+func TestStreamingAggregators(t *testing.T) {
+	w := NewMinWindow[float64](3)
+	expected := []struct {
+		v, min float64
+		ok     bool
+	}{
+		{5, 5, false}, {3, 3, false}, {4, 3, true}, {6, 3, true}, {7, 4, true}, {1, 1, true},
+	}
+	for i, e := range expected {
+		if m, ok := w.Push(e.v); m != e.min || ok != e.ok {
+			t.Errorf("push %d: expected %v, %v; got: %v, %v", i, e.min, e.ok, m, ok)
+		}
+	}
+
+	r := NewRollingWindow[int](2)
+	if r.Push(4) || r.Mean() != 4 {
+		t.Errorf("expected partial window with mean 4; got: %v", r.Mean())
+	}
+	if !r.Push(6) || r.Sum() != 10 || !r.Push(10) || r.Sum() != 16 {
+		t.Errorf("unexpected rolling sum: %d", r.Sum())
+	}
+
+	var c CumulativeSum[int]
+	c.Push(1)
+	if got := c.Push(2); got != 3 {
+		t.Errorf("expected cumulative sum 3; got: %d", got)
+	}
+}
+
+func TestEMAAndCumSum(t *testing.T) {
+	got := ExpMovingAverage([]int{10, 20, 20}, 0.5)
+	if expected := []float64{10, 15, 17.5}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v; got: %v", expected, got)
+	}
+	e := NewEMA(1)
+	e.Push(3)
+	if v := e.Push(8); v != 8 {
+		t.Errorf("alpha 1 must track the last value; got: %v", v)
+	}
+
+	if got := CumSum(Slice{1, 2, 3, 4}); !reflect.DeepEqual(Slice(got), Slice{1, 3, 6, 10}) {
+		t.Errorf("expected [1 3 6 10]; got: %v", got)
+	}
+}
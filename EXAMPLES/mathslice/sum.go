@@ -0,0 +1,110 @@
+package mathslice
+
+import "math"
+
+// Float — ограничение для чисел с плавающей точкой
+type Float interface {
+	~float32 | ~float64
+}
+
+// SumMode — способ суммирования чисел с плавающей точкой
+type SumMode int
+
+const (
+	// SumNaive — обычная накапливающаяся сумма, как в SumSlice. Ошибка растёт линейно с длиной слайса
+	SumNaive SumMode = iota
+	// SumKahan — компенсированное суммирование Кэхэна в варианте Ноймайера:
+	// потерянные младшие разряды копятся в отдельной поправке. Ошибка не зависит от длины слайса
+	SumKahan
+	// SumPairwise — попарное (каскадное) суммирование. Ошибка растёт как логарифм длины слайса
+	SumPairwise
+)
+
+// pairwiseBlock — длина куска, который при попарном суммировании складывается обычным циклом
+const pairwiseBlock = 128
+
+func (m SumMode) String() string {
+	switch m {
+	case SumNaive:
+		return "naive"
+	case SumKahan:
+		return "kahan"
+	case SumPairwise:
+		return "pairwise"
+	default:
+		return "unknown"
+	}
+}
+
+// SumFloats — сумма элементов слайса выбранным способом.
+// SumKahan и SumPairwise накапливают сумму в float64 даже для float32
+func SumFloats[T Float](s []T, mode SumMode) T {
+	term := func(i int) (float64, float64) { return float64(s[i]), 0 }
+	switch mode {
+	case SumKahan:
+		return T(neumaierSum(len(s), term))
+	case SumPairwise:
+		return T(pairwiseSum(0, len(s), term))
+	default:
+		return SumSlice(s)
+	}
+}
+
+// Dot — скалярное произведение a и b с тем же способом накопления, что и в SumFloats.
+// В режиме SumKahan учитывается и ошибка округления каждого произведения (через math.FMA).
+// Слайсы должны быть одной длины, иначе Dot паникует
+func Dot[T Float](a, b []T, mode SumMode) T {
+	if len(a) != len(b) {
+		panic("mathslice: dot product of slices with different lengths")
+	}
+	term := func(i int) (float64, float64) {
+		x, y := float64(a[i]), float64(b[i])
+		p := x * y
+		return p, math.FMA(x, y, -p)
+	}
+	switch mode {
+	case SumKahan:
+		return T(neumaierSum(len(a), term))
+	case SumPairwise:
+		return T(pairwiseSum(0, len(a), term))
+	default:
+		var res T
+		for i := range a {
+			res += a[i] * b[i]
+		}
+		return res
+	}
+}
+
+// neumaierSum — компенсированная сумма n слагаемых. term возвращает слагаемое
+// и уже известную ошибку его вычисления, которая сразу добавляется к поправке
+func neumaierSum(n int, term func(i int) (float64, float64)) float64 {
+	var sum, comp float64
+	for i := 0; i < n; i++ {
+		v, e := term(i)
+		t := sum + v
+		if math.Abs(sum) >= math.Abs(v) {
+			comp += (sum - t) + v
+		} else {
+			comp += (v - t) + sum
+		}
+		sum = t
+		comp += e
+	}
+	return sum + comp
+}
+
+// pairwiseSum — сумма слагаемых с индексами [lo, hi): диапазон делится пополам,
+// пока не станет короче pairwiseBlock. Рекурсия неглубокая — log2(n/pairwiseBlock) уровней
+func pairwiseSum(lo, hi int, term func(i int) (float64, float64)) float64 {
+	if hi-lo <= pairwiseBlock {
+		var sum float64
+		for i := lo; i < hi; i++ {
+			v, _ := term(i)
+			sum += v
+		}
+		return sum
+	}
+	mid := lo + (hi-lo)/2
+	return pairwiseSum(lo, mid, term) + pairwiseSum(mid, hi, term)
+}
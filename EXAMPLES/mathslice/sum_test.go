@@ -0,0 +1,123 @@
+package mathslice
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// exactSum — эталонная сумма через big.Float; точности 4096 бит хватает,
+// чтобы сложить любые float64 без округления
+func exactSum(s []float64) float64 {
+	acc := new(big.Float).SetPrec(4096)
+	for _, v := range s {
+		acc.Add(acc, new(big.Float).SetPrec(4096).SetFloat64(v))
+	}
+	res, _ := acc.Float64()
+	return res
+}
+
+func exactDot(a, b []float64) float64 {
+	acc := new(big.Float).SetPrec(4096)
+	for i := range a {
+		p := new(big.Float).SetPrec(4096).SetFloat64(a[i])
+		p.Mul(p, new(big.Float).SetPrec(4096).SetFloat64(b[i]))
+		acc.Add(acc, p)
+	}
+	res, _ := acc.Float64()
+	return res
+}
+
+func relErr(got, expected float64) float64 {
+	if expected == 0 {
+		return math.Abs(got)
+	}
+	return math.Abs(got-expected) / math.Abs(expected)
+}
+
+func TestSumFloatsCancellation(t *testing.T) {
+	// большие слагаемые взаимно уничтожаются, а единицы при обычном суммировании теряются
+	var s []float64
+	for i := 0; i < 1000; i++ {
+		s = append(s, 1, 1e100, 1, -1e100)
+	}
+	expected := exactSum(s)
+	if expected != 2000 {
+		t.Fatalf("reference sum: expected 2000; got: %v", expected)
+	}
+
+	naive := SumFloats(s, SumNaive)
+	t.Logf("naive sum %v, absolute error %v", naive, math.Abs(naive-expected))
+	if naive == expected {
+		t.Errorf("adversarial input must break naive summation")
+	}
+	if got := SumFloats(s, SumKahan); got != expected {
+		t.Errorf("kahan: expected %v; got: %v", expected, got)
+	}
+}
+
+func TestSumFloatsLongSlice(t *testing.T) {
+	s := make([]float64, 1<<20)
+	for i := range s {
+		s[i] = 0.1
+	}
+	expected := exactSum(s)
+
+	naiveErr := relErr(SumFloats(s, SumNaive), expected)
+	kahanErr := relErr(SumFloats(s, SumKahan), expected)
+	pairwiseErr := relErr(SumFloats(s, SumPairwise), expected)
+	t.Logf("relative error: naive %.3g, kahan %.3g, pairwise %.3g", naiveErr, kahanErr, pairwiseErr)
+
+	if kahanErr > 1e-16 {
+		t.Errorf("kahan: relative error too large: %g", kahanErr)
+	}
+	if pairwiseErr > 1e-14 || pairwiseErr >= naiveErr {
+		t.Errorf("pairwise: relative error %g, naive %g", pairwiseErr, naiveErr)
+	}
+}
+
+func TestSumFloatsRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := make([]float64, 10000)
+	for i := range s {
+		s[i] = (rnd.Float64() - 0.5) * math.Pow(10, float64(rnd.Intn(20)))
+	}
+	expected := exactSum(s)
+	for _, mode := range []SumMode{SumKahan, SumPairwise} {
+		if err := relErr(SumFloats(s, mode), expected); err > 1e-12 {
+			t.Errorf("%s: relative error too large: %g", mode, err)
+		}
+	}
+}
+
+func TestDot(t *testing.T) {
+	// (1e8+1)(1e8-1) = 1e16-1 округляется до 1e16, и после вычитания 1e16 остаётся ноль
+	a := []float64{1e8 + 1, -1}
+	b := []float64{1e8 - 1, 1e16}
+	expected := exactDot(a, b)
+	if expected != -1 {
+		t.Fatalf("reference dot: expected -1; got: %v", expected)
+	}
+
+	naive := Dot(a, b, SumNaive)
+	t.Logf("naive dot %v, expected %v", naive, expected)
+	if naive == expected {
+		t.Errorf("adversarial input must break naive dot product")
+	}
+	if got := Dot(a, b, SumKahan); got != expected {
+		t.Errorf("kahan: expected %v; got: %v", expected, got)
+	}
+
+	ones := make([]float64, 1<<16)
+	tenths := make([]float64, len(ones))
+	for i := range ones {
+		ones[i], tenths[i] = 1, 0.1
+	}
+	expected = exactDot(ones, tenths)
+	pairwiseErr := relErr(Dot(ones, tenths, SumPairwise), expected)
+	naiveErr := relErr(Dot(ones, tenths, SumNaive), expected)
+	if pairwiseErr > 1e-14 || pairwiseErr >= naiveErr {
+		t.Errorf("pairwise: relative error %g, naive %g", pairwiseErr, naiveErr)
+	}
+}
@@ -1,8 +1,9 @@
 package main
 
 import (
-	"exercise/mathslice"
 	"fmt"
+
+	"arrint.go/EXAMPLES/mathslice"
 )
 
 func main() {
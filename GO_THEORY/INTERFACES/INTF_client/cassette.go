@@ -0,0 +1,255 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ErrUnexpectedCall — при строгом воспроизведении вызов не совпал с записанным
+var ErrUnexpectedCall = errors.New("client: unexpected call")
+
+// Названия методов в кассете
+const (
+	MethodFetchMessages = "FetchMessages"
+	MethodSendMessage   = "SendMessage"
+)
+
+// Interaction — один записанный вызов: метод, аргументы и результат
+type Interaction struct {
+	Method   string    `json:"method"`
+	Email    string    `json:"email,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	Messages []Message `json:"messages,omitempty"`
+	// Err — текст ошибки; пусто, если вызов удался
+	Err string `json:"error,omitempty"`
+}
+
+// matches — тот же ли это вызов: метод и аргументы совпадают
+func (i Interaction) matches(method, email, message string) bool {
+	return i.Method == method && i.Email == email && i.Message == message
+}
+
+// err — ошибка вызова. Ошибки пакета восстанавливаются как есть, чтобы errors.Is работал и при воспроизведении
+func (i Interaction) err() error {
+	if i.Err == "" {
+		return nil
+	}
+	for _, known := range []error{ErrNotConnected, ErrTimeout, ErrCircuitOpen, ErrClosed} {
+		if i.Err == known.Error() {
+			return known
+		}
+	}
+	return errors.New(i.Err)
+}
+
+func (i Interaction) String() string {
+	if i.Method == MethodSendMessage {
+		return fmt.Sprintf("%s(%q, %q)", i.Method, i.Email, i.Message)
+	}
+	return i.Method + "()"
+}
+
+// Cassette — записанные вызовы в порядке их выполнения
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette — читает кассету из JSON-файла
+func LoadCassette(path string) (*Cassette, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("client: cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save — записывает кассету в JSON-файл
+func (c *Cassette) Save(path string) error {
+	raw, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o644)
+}
+
+// Recorder — обёртка над настоящим Client, которая записывает все вызовы и их результаты.
+// Записанное можно сохранить в кассету и потом воспроизвести в тестах через Replayer
+type Recorder struct {
+	client Client
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder — оборачивает client
+func NewRecorder(client Client) *Recorder {
+	return &Recorder{client: client}
+}
+
+// FetchMessages — FetchMessages обёрнутого клиента с записью результата
+func (r *Recorder) FetchMessages() ([]Message, error) {
+	messages, err := r.client.FetchMessages()
+	r.record(Interaction{Method: MethodFetchMessages, Messages: messages}, err)
+	return messages, err
+}
+
+// SendMessage — SendMessage обёрнутого клиента с записью результата
+func (r *Recorder) SendMessage(email string, message string) error {
+	err := r.client.SendMessage(email, message)
+	r.record(Interaction{Method: MethodSendMessage, Email: email, Message: message}, err)
+	return err
+}
+
+func (r *Recorder) record(i Interaction, err error) {
+	if err != nil {
+		i.Err = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+}
+
+// Cassette — копия записанного на данный момент
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save — сохраняет записанное в файл
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+// ReplayMode — насколько строго Replayer сверяет вызовы с кассетой
+type ReplayMode int
+
+const (
+	// Strict — вызовы должны идти ровно в записанном порядке с теми же аргументами,
+	// любой другой вызов завершается ErrUnexpectedCall
+	Strict ReplayMode = iota
+	// Lenient — порядок не важен: берётся первый неиспользованный подходящий вызов,
+	// а на незаписанный вызов возвращается пустой результат без ошибки
+	Lenient
+)
+
+// Replayer — Client, который вместо обращения к API отвечает записанным в кассету
+type Replayer struct {
+	mode ReplayMode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	next         int
+	unexpected   []string
+}
+
+// NewReplayer — воспроизводит cassette в режиме mode
+func NewReplayer(cassette *Cassette, mode ReplayMode) *Replayer {
+	interactions := append([]Interaction(nil), cassette.Interactions...)
+	return &Replayer{mode: mode, interactions: interactions, used: make([]bool, len(interactions))}
+}
+
+// LoadReplayer — читает кассету из файла и воспроизводит её в режиме mode
+func LoadReplayer(path string, mode ReplayMode) (*Replayer, error) {
+	c, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(c, mode), nil
+}
+
+// FetchMessages — записанный результат FetchMessages
+func (r *Replayer) FetchMessages() ([]Message, error) {
+	i, err := r.take(Interaction{Method: MethodFetchMessages})
+	if err != nil || i == nil {
+		return nil, err
+	}
+	return append([]Message(nil), i.Messages...), i.err()
+}
+
+// SendMessage — записанный результат SendMessage с теми же аргументами
+func (r *Replayer) SendMessage(email string, message string) error {
+	i, err := r.take(Interaction{Method: MethodSendMessage, Email: email, Message: message})
+	if err != nil || i == nil {
+		return err
+	}
+	return i.err()
+}
+
+// take — находит записанный вызов, подходящий под call. В мягком режиме nil без ошибки означает,
+// что такого вызова в кассете нет
+func (r *Replayer) take(call Interaction) (*Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == Strict {
+		if r.next >= len(r.interactions) {
+			return nil, r.unexpectedLocked(call, "cassette is over")
+		}
+		i := &r.interactions[r.next]
+		if !i.matches(call.Method, call.Email, call.Message) {
+			return nil, r.unexpectedLocked(call, fmt.Sprintf("expected %v", i))
+		}
+		r.used[r.next] = true
+		r.next++
+		return i, nil
+	}
+
+	for n := range r.interactions {
+		if !r.used[n] && r.interactions[n].matches(call.Method, call.Email, call.Message) {
+			r.used[n] = true
+			return &r.interactions[n], nil
+		}
+	}
+	r.unexpected = append(r.unexpected, call.String())
+	return nil, nil
+}
+
+func (r *Replayer) unexpectedLocked(call Interaction, reason string) error {
+	r.unexpected = append(r.unexpected, call.String())
+	return fmt.Errorf("%w %v: %s", ErrUnexpectedCall, call, reason)
+}
+
+// Unexpected — вызовы, которых не было в кассете
+func (r *Replayer) Unexpected() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.unexpected...)
+}
+
+// Remaining — записанные вызовы, до которых дело так и не дошло
+func (r *Replayer) Remaining() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var res []Interaction
+	for n, i := range r.interactions {
+		if !r.used[n] {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Done — ошибка, если в строгом режиме были лишние вызовы или кассета воспроизведена не целиком.
+// Удобно вызывать в конце теста
+func (r *Replayer) Done() error {
+	unexpected, remaining := r.Unexpected(), r.Remaining()
+	if r.mode == Lenient || (len(unexpected) == 0 && len(remaining) == 0) {
+		return nil
+	}
+	return fmt.Errorf("client: replay incomplete: %d unexpected calls %v, %d calls not replayed %v",
+		len(unexpected), unexpected, len(remaining), remaining)
+}
+
+var (
+	_ Client = (*Recorder)(nil)
+	_ Client = (*Replayer)(nil)
+)
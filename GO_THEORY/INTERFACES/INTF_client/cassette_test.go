@@ -0,0 +1,92 @@
+package client
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// recordSession — записывает короткий разговор с BigAPIClient и сохраняет кассету
+func recordSession(t *testing.T) string {
+	t.Helper()
+	api := &BigAPIClient{}
+	rec := NewRecorder(api)
+	if _, err := rec.FetchMessages(); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected; got: %v", err)
+	}
+	api.Connect()
+	if err := rec.SendMessage("bob@example.com", "привет"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.FetchMessages(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReplayStrict(t *testing.T) {
+	path := recordSession(t)
+	r, err := LoadReplayer(path, Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.FetchMessages(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("recorded error must be replayed; got: %v", err)
+	}
+	if err := r.SendMessage("alice@example.com", "привет"); !errors.Is(err, ErrUnexpectedCall) {
+		t.Errorf("expected ErrUnexpectedCall for other arguments; got: %v", err)
+	}
+	if err := r.SendMessage("bob@example.com", "привет"); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := MyFunc(r)
+	expected := []Message{{ID: "1", Email: "bob@example.com", Text: "привет"}}
+	if err != nil || !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("expected %v; got: %v, %v", expected, msgs, err)
+	}
+	if _, err := r.FetchMessages(); !errors.Is(err, ErrUnexpectedCall) {
+		t.Errorf("expected ErrUnexpectedCall after the cassette is over; got: %v", err)
+	}
+	if err := r.Done(); err == nil {
+		t.Errorf("unexpected calls must be reported by Done")
+	}
+}
+
+func TestReplayLenient(t *testing.T) {
+	path := recordSession(t)
+	c, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Interactions) != 3 {
+		t.Fatalf("expected 3 interactions; got: %v", c.Interactions)
+	}
+	r := NewReplayer(c, Lenient)
+
+	// порядок не важен: SendMessage раньше записанного первым FetchMessages
+	if err := r.SendMessage("bob@example.com", "привет"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SendMessage("alice@example.com", "не записано"); err != nil {
+		t.Errorf("lenient replay must accept unexpected calls; got: %v", err)
+	}
+	if _, err := r.FetchMessages(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("expected the first recorded fetch; got: %v", err)
+	}
+	if remaining := r.Remaining(); len(remaining) != 1 || remaining[0].Method != MethodFetchMessages {
+		t.Errorf("expected one fetch left; got: %v", remaining)
+	}
+	if unexpected := r.Unexpected(); len(unexpected) != 1 {
+		t.Errorf("expected one unexpected call; got: %v", unexpected)
+	}
+	if err := r.Done(); err != nil {
+		t.Errorf("lenient replay must not fail: %v", err)
+	}
+}
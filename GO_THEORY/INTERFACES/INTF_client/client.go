@@ -0,0 +1,138 @@
+// Package client — пример из раздела об интерфейсах и коде внешних библиотек:
+// библиотечный BigAPIClient, узкий интерфейс Client, которого хватает нашему коду,
+// и тестовая заглушка MockClient
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotConnected — метод вызван до Connect или после Close
+var ErrNotConnected = errors.New("client: not connected")
+
+// Message — сообщение из API
+type Message struct {
+	// ID — постоянный номер сообщения, по нему можно отсеять повторы
+	ID    string `json:"id"`
+	Email string `json:"email,omitempty"`
+	Text  string `json:"text"`
+}
+
+// Client — то, что нашему коду нужно от библиотеки. *BigAPIClient реализует его, ничего о нём не зная
+type Client interface {
+	FetchMessages() ([]Message, error)
+	SendMessage(email string, message string) error
+}
+
+// BigAPIClient — клиент из сторонней библиотеки, который посылает сетевые запросы к API.
+// Здесь вместо сети — почтовый ящик в памяти
+type BigAPIClient struct {
+	mu        sync.Mutex
+	connected bool
+	inbox     []Message
+	status    string
+	nextID    int
+	// keys — ключи идемпотентности уже отправленных сообщений
+	keys map[string]bool
+}
+
+// Connect — подключается к API
+func (c *BigAPIClient) Connect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connected = true
+	return nil
+}
+
+// Close — отключается от API
+func (c *BigAPIClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return ErrNotConnected
+	}
+	c.connected = false
+	return nil
+}
+
+// Ping — проверяет подключение к API
+func (c *BigAPIClient) Ping() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return ErrNotConnected
+	}
+	return nil
+}
+
+// FetchMessages — забирает новые сообщения
+func (c *BigAPIClient) FetchMessages() ([]Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return nil, ErrNotConnected
+	}
+	res := c.inbox
+	c.inbox = nil
+	return res, nil
+}
+
+// SendMessage — отправляет сообщение; в примере оно возвращается в наш же ящик
+func (c *BigAPIClient) SendMessage(email string, message string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return ErrNotConnected
+	}
+	c.nextID++
+	c.inbox = append(c.inbox, Message{ID: fmt.Sprint(c.nextID), Email: email, Text: message})
+	return nil
+}
+
+// SendMessageWithKey — отправляет сообщение с ключом идемпотентности: API запоминает ключ,
+// и повторная отправка с тем же ключом ничего не делает
+func (c *BigAPIClient) SendMessageWithKey(key, email, message string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return ErrNotConnected
+	}
+	if c.keys[key] {
+		return nil
+	}
+	if c.keys == nil {
+		c.keys = make(map[string]bool)
+	}
+	c.keys[key] = true
+	c.nextID++
+	c.inbox = append(c.inbox, Message{ID: fmt.Sprint(c.nextID), Email: email, Text: message})
+	return nil
+}
+
+// SendStatus — сообщает API свой статус, например online
+func (c *BigAPIClient) SendStatus(status string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return ErrNotConnected
+	}
+	c.status = status
+	return nil
+}
+
+// MyFunc — в параметрах вместо типа *BigAPIClient принимаем интерфейс Client,
+// поэтому функция одинаково работает и с библиотекой, и с MockClient
+func MyFunc(client Client) ([]Message, error) {
+	messages, err := client.FetchMessages()
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+var (
+	_ Client           = (*BigAPIClient)(nil)
+	_ IdempotentSender = (*BigAPIClient)(nil)
+)
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrClosed — управляемый клиент закрыт или его контекст отменён
+var ErrClosed = errors.New("client: closed")
+
+// Conn — одно подключение к API с ручным управлением, например *BigAPIClient.
+// Ping проверяет подключение и ничего не меняет в API
+type Conn interface {
+	Client
+	Connect() error
+	Close() error
+	Ping() error
+}
+
+// StatusSender — подключение, которое умеет сообщать статус, например *BigAPIClient
+type StatusSender interface {
+	SendStatus(status string) error
+}
+
+// ManagedOptions — настройки Managed. Нулевые значения заменяются значениями по умолчанию
+type ManagedOptions struct {
+	// Size — сколько подключений держать. Одновременно выполняется не больше Size вызовов. По умолчанию 4
+	Size int
+	// Dial — создаёт ещё не подключённый клиент. По умолчанию &BigAPIClient{}
+	Dial func() Conn
+	// HealthInterval и HealthCheck — как часто и чем проверять простаивающие подключения.
+	// По умолчанию раз в 30 с вызывается Ping. Отрицательный HealthInterval отключает проверки:
+	// тогда потерянное подключение обнаруживается и восстанавливается при следующем вызове
+	HealthInterval time.Duration
+	HealthCheck    func(Conn) error
+	// OnError — куда сообщать, что подключение не прошло проверку и переподключиться не удалось.
+	// Такое подключение остаётся отключённым, и следующий вызов попробует подключиться снова.
+	// По умолчанию ошибки отбрасываются
+	OnError func(error)
+	// Dropped — означает ли ошибка, что подключение потеряно и его нужно восстановить.
+	// По умолчанию — errors.Is(err, ErrNotConnected)
+	Dropped func(error) bool
+}
+
+// slot — место в пуле; conn создаётся при первом использовании
+type slot struct {
+	conn      Conn
+	connected bool
+}
+
+// Managed — клиент, который сам управляет подключениями: подключается при первом вызове,
+// проверяет простаивающие подключения, восстанавливает потерянные и держит несколько
+// подключений, чтобы одновременные вызовы не ждали друг друга.
+// Managed реализует Client, поэтому его можно передать в MyFunc вместо подключённого BigAPIClient.
+// Статус хранится у подключения, поэтому SendStatus всегда идёт через одно отдельное подключение,
+// а не через пул. Когда ctx отменяется, Managed закрывается сам
+type Managed struct {
+	opts ManagedOptions
+	// pool — свободные места; занятое место возвращается после вызова
+	pool chan *slot
+	// status — подключение для SendStatus; statusMu — оно занято
+	status   *slot
+	statusMu sync.Mutex
+	done     chan struct{}
+
+	closeOnce sync.Once
+	closeErr  error
+	wg        sync.WaitGroup
+}
+
+// NewManaged — создаёт управляемый клиент. Подключения создаются по мере надобности
+func NewManaged(ctx context.Context, opts ManagedOptions) *Managed {
+	if opts.Size <= 0 {
+		opts.Size = 4
+	}
+	if opts.Dial == nil {
+		opts.Dial = func() Conn { return &BigAPIClient{} }
+	}
+	if opts.HealthInterval == 0 {
+		opts.HealthInterval = 30 * time.Second
+	}
+	if opts.HealthCheck == nil {
+		opts.HealthCheck = func(c Conn) error { return c.Ping() }
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	if opts.Dropped == nil {
+		opts.Dropped = func(err error) bool { return errors.Is(err, ErrNotConnected) }
+	}
+
+	m := &Managed{opts: opts, pool: make(chan *slot, opts.Size), status: &slot{}, done: make(chan struct{})}
+	for i := 0; i < opts.Size; i++ {
+		m.pool <- &slot{}
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		var tick <-chan time.Time
+		if opts.HealthInterval > 0 {
+			ticker := time.NewTicker(opts.HealthInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				go m.Close()
+				return
+			case <-m.done:
+				return
+			case <-tick:
+				m.checkIdle()
+			}
+		}
+	}()
+	return m
+}
+
+// FetchMessages — FetchMessages через свободное подключение
+func (m *Managed) FetchMessages() ([]Message, error) {
+	var res []Message
+	err := m.call(func(c Conn) error {
+		var err error
+		res, err = c.FetchMessages()
+		return err
+	})
+	return res, err
+}
+
+// SendMessage — SendMessage через свободное подключение
+func (m *Managed) SendMessage(email string, message string) error {
+	return m.call(func(c Conn) error { return c.SendMessage(email, message) })
+}
+
+// SendStatus — SendStatus через подключение для статуса; ошибка, если подключения статус не поддерживают
+func (m *Managed) SendStatus(status string) error {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	select {
+	case <-m.done:
+		return ErrClosed
+	default:
+	}
+	return m.callOn(m.status, func(c Conn) error {
+		s, ok := c.(StatusSender)
+		if !ok {
+			return errors.New("client: connection does not support SendStatus")
+		}
+		return s.SendStatus(status)
+	})
+}
+
+// call — выполняет f на свободном подключении из пула
+func (m *Managed) call(f func(Conn) error) error {
+	s, err := m.acquire()
+	if err != nil {
+		return err
+	}
+	defer m.release(s)
+	return m.callOn(s, f)
+}
+
+// callOn — выполняет f на подключении s. Если подключение потеряно,
+// оно восстанавливается, и f повторяется один раз
+func (m *Managed) callOn(s *slot, f func(Conn) error) error {
+	if err := m.connect(s); err != nil {
+		return err
+	}
+	err := f(s.conn)
+	if err == nil || !m.opts.Dropped(err) {
+		return err
+	}
+	m.disconnect(s)
+	if err := m.connect(s); err != nil {
+		return err
+	}
+	return f(s.conn)
+}
+
+// acquire — ждёт свободное место в пуле
+func (m *Managed) acquire() (*slot, error) {
+	select {
+	case <-m.done:
+		return nil, ErrClosed
+	default:
+	}
+	select {
+	case s := <-m.pool:
+		select {
+		case <-m.done:
+			m.release(s)
+			return nil, ErrClosed
+		default:
+			return s, nil
+		}
+	case <-m.done:
+		return nil, ErrClosed
+	}
+}
+
+func (m *Managed) release(s *slot) {
+	m.pool <- s
+}
+
+// connect — подключает место, если оно ещё не подключено
+func (m *Managed) connect(s *slot) error {
+	if s.connected {
+		return nil
+	}
+	if s.conn == nil {
+		s.conn = m.opts.Dial()
+	}
+	if err := s.conn.Connect(); err != nil {
+		return err
+	}
+	s.connected = true
+	return nil
+}
+
+// disconnect — закрывает подключение; ошибка закрытия уже потерянного подключения неинтересна
+func (m *Managed) disconnect(s *slot) {
+	if s.connected {
+		s.conn.Close()
+		s.connected = false
+	}
+}
+
+// checkIdle — проверяет свободные подключения и переподключает те, что не прошли проверку.
+// Занятые подключения не трогает: их проверит сам вызов. Отключённые тоже: они подключатся при следующем вызове
+func (m *Managed) checkIdle() {
+	for i := 0; i < m.opts.Size; i++ {
+		var s *slot
+		select {
+		case s = <-m.pool:
+		default:
+			return
+		}
+		if s.connected {
+			if err := m.opts.HealthCheck(s.conn); err != nil {
+				m.disconnect(s)
+				if err := m.connect(s); err != nil {
+					m.opts.OnError(fmt.Errorf("client: reconnect after failed health check: %w", err))
+				}
+			}
+		}
+		m.release(s)
+	}
+}
+
+// Close — закрывает все подключения, дождавшись окончания идущих вызовов.
+// Новые вызовы после Close завершаются ErrClosed. Повторный Close возвращает тот же результат
+func (m *Managed) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.done)
+		slots := make([]*slot, 0, m.opts.Size+1)
+		for i := 0; i < m.opts.Size; i++ {
+			slots = append(slots, <-m.pool)
+		}
+		m.statusMu.Lock()
+		slots = append(slots, m.status)
+		for _, s := range slots {
+			if s.connected {
+				if err := s.conn.Close(); err != nil && m.closeErr == nil {
+					m.closeErr = err
+				}
+				s.connected = false
+			}
+		}
+		m.statusMu.Unlock()
+	})
+	m.wg.Wait()
+	return m.closeErr
+}
+
+var (
+	_ Client       = (*Managed)(nil)
+	_ StatusSender = (*Managed)(nil)
+	_ Conn         = (*BigAPIClient)(nil)
+)
@@ -0,0 +1,311 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeConn — подключение, которое можно «уронить» и которое считает одновременные вызовы
+type fakeConn struct {
+	*BigAPIClient
+	delay time.Duration
+	stats *connStats
+}
+
+// connCounts — сколько раз подключения создавались, подключались и закрывались
+type connCounts struct {
+	dials     int
+	connects  int
+	closes    int
+	maxActive int
+}
+
+type connStats struct {
+	mu sync.Mutex
+	connCounts
+	active int
+	// connectErr — если задана, Connect завершается ею
+	connectErr error
+}
+
+func (c *fakeConn) Connect() error {
+	c.stats.mu.Lock()
+	c.stats.connects++
+	err := c.stats.connectErr
+	c.stats.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return c.BigAPIClient.Connect()
+}
+
+func (c *fakeConn) Close() error {
+	c.stats.mu.Lock()
+	c.stats.closes++
+	c.stats.mu.Unlock()
+	return c.BigAPIClient.Close()
+}
+
+func (c *fakeConn) FetchMessages() ([]Message, error) {
+	c.stats.mu.Lock()
+	c.stats.active++
+	if c.stats.active > c.stats.maxActive {
+		c.stats.maxActive = c.stats.active
+	}
+	c.stats.mu.Unlock()
+	time.Sleep(c.delay)
+	c.stats.mu.Lock()
+	c.stats.active--
+	c.stats.mu.Unlock()
+	return c.BigAPIClient.FetchMessages()
+}
+
+// drop — связь пропала: клиент библиотеки считает себя отключённым
+func (c *fakeConn) drop() {
+	c.BigAPIClient.Close()
+}
+
+func (s *connStats) get() connCounts {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connCounts
+}
+
+func (s *connStats) failConnect(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connectErr = err
+}
+
+func newFakeDial(stats *connStats, delay time.Duration, conns *[]*fakeConn) func() Conn {
+	return func() Conn {
+		stats.mu.Lock()
+		defer stats.mu.Unlock()
+		stats.dials++
+		c := &fakeConn{BigAPIClient: &BigAPIClient{}, delay: delay, stats: stats}
+		*conns = append(*conns, c)
+		return c
+	}
+}
+
+func TestManagedLazyAndReconnect(t *testing.T) {
+	stats := &connStats{}
+	var conns []*fakeConn
+	m := NewManaged(context.Background(), ManagedOptions{Size: 1, Dial: newFakeDial(stats, 0, &conns)})
+	defer m.Close()
+
+	if got := stats.get(); got.dials != 0 {
+		t.Fatalf("managed client must connect lazily; got %d dials", got.dials)
+	}
+	if _, err := MyFunc(m); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SendMessage("bob@example.com", "привет"); err != nil {
+		t.Fatal(err)
+	}
+	if got := stats.get(); got.dials != 1 || got.connects != 1 {
+		t.Errorf("expected one connection; got: %+v", got)
+	}
+
+	conns[0].drop()
+	msgs, err := m.FetchMessages()
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("expected reconnect and the message; got: %v, %v", msgs, err)
+	}
+	if got := stats.get(); got.connects != 2 {
+		t.Errorf("expected reconnect; got: %+v", got)
+	}
+}
+
+func TestManagedPool(t *testing.T) {
+	stats := &connStats{}
+	var conns []*fakeConn
+	m := NewManaged(context.Background(), ManagedOptions{Size: 4, Dial: newFakeDial(stats, 20*time.Millisecond, &conns)})
+	defer m.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.FetchMessages(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	got := stats.get()
+	if got.maxActive < 2 || got.maxActive > 4 {
+		t.Errorf("expected between 2 and 4 concurrent calls; got: %d", got.maxActive)
+	}
+	if got.dials > 4 {
+		t.Errorf("pool must not grow beyond its size; got %d dials", got.dials)
+	}
+}
+
+func TestManagedHealthCheck(t *testing.T) {
+	stats := &connStats{}
+	var conns []*fakeConn
+	var mu sync.Mutex
+	healthy := true
+	m := NewManaged(context.Background(), ManagedOptions{
+		Size:           1,
+		Dial:           newFakeDial(stats, 0, &conns),
+		HealthInterval: time.Millisecond,
+		HealthCheck: func(c Conn) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if !healthy {
+				healthy = true
+				return errors.New("ping failed")
+			}
+			return nil
+		},
+	})
+	defer m.Close()
+
+	if err := m.SendMessage("bob@example.com", "привет"); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	healthy = false
+	mu.Unlock()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for stats.get().connects < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("failed health check must reconnect; got: %+v", stats.get())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// status — статус, который запомнило подключение
+func (c *fakeConn) status() string {
+	c.BigAPIClient.mu.Lock()
+	defer c.BigAPIClient.mu.Unlock()
+	return c.BigAPIClient.status
+}
+
+func TestManagedDefaultHealthCheck(t *testing.T) {
+	stats := &connStats{}
+	var conns []*fakeConn
+	m := NewManaged(context.Background(), ManagedOptions{Size: 1, Dial: newFakeDial(stats, 0, &conns), HealthInterval: time.Millisecond})
+	defer m.Close()
+
+	if err := m.SendStatus("away"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.FetchMessages(); err != nil {
+		t.Fatal(err)
+	}
+	conns[1].drop()
+
+	// Ping замечает потерянное подключение без всяких вызовов и ничего не меняет в API
+	deadline := time.Now().Add(5 * time.Second)
+	for stats.get().connects < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("default health check must reconnect; got: %+v", stats.get())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := conns[0].status(); got != "away" {
+		t.Errorf("health checks must not change the status; got: %q", got)
+	}
+}
+
+func TestManagedHealthCheckDisabled(t *testing.T) {
+	stats := &connStats{}
+	var conns []*fakeConn
+	m := NewManaged(context.Background(), ManagedOptions{Size: 1, Dial: newFakeDial(stats, 0, &conns), HealthInterval: -1})
+	defer m.Close()
+
+	if _, err := m.FetchMessages(); err != nil {
+		t.Fatal(err)
+	}
+	conns[0].drop()
+	time.Sleep(20 * time.Millisecond)
+	if got := stats.get(); got.connects != 1 {
+		t.Errorf("disabled health checks must not reconnect; got: %+v", got)
+	}
+}
+
+func TestManagedHealthCheckReconnectFails(t *testing.T) {
+	stats := &connStats{}
+	var conns []*fakeConn
+	errs := make(chan error, 100)
+	m := NewManaged(context.Background(), ManagedOptions{
+		Size:           1,
+		Dial:           newFakeDial(stats, 0, &conns),
+		HealthInterval: time.Millisecond,
+		OnError:        func(err error) { errs <- err },
+	})
+	defer m.Close()
+
+	if _, err := m.FetchMessages(); err != nil {
+		t.Fatal(err)
+	}
+	down := errors.New("connection refused")
+	stats.failConnect(down)
+	conns[0].drop()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, down) {
+			t.Errorf("expected %v; got: %v", down, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed reconnect must be reported")
+	}
+	// отключённое подключение проверки не трогают, поэтому ошибка не повторяется
+	time.Sleep(20 * time.Millisecond)
+	if n := len(errs); n != 0 {
+		t.Errorf("disconnected slot must be left for the next call; got %d more errors", n)
+	}
+
+	stats.failConnect(nil)
+	if _, err := m.FetchMessages(); err != nil {
+		t.Errorf("next call must reconnect; got: %v", err)
+	}
+}
+
+func TestManagedCloseOnCancel(t *testing.T) {
+	stats := &connStats{}
+	var conns []*fakeConn
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewManaged(ctx, ManagedOptions{Size: 2, Dial: newFakeDial(stats, 20*time.Millisecond, &conns)})
+
+	started := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		close(started)
+		_, err := m.FetchMessages()
+		result <- err
+	}()
+	<-started
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+
+	if err := <-result; err != nil && !errors.Is(err, ErrClosed) {
+		t.Errorf("call in progress must finish or see ErrClosed; got: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := m.FetchMessages(); errors.Is(err, ErrClosed) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("managed client must close when its context is cancelled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+	if got := stats.get(); got.closes != got.connects {
+		t.Errorf("every connection must be closed: %+v", got)
+	}
+}
@@ -0,0 +1,91 @@
+package client
+
+import "sync"
+
+// MockClient — тестовая заглушка для Client. По умолчанию FetchMessages возвращает Messages
+// (или два сообщения из примера, если Messages не задан), а SendMessage успешно «отправляет» сообщение в Sent.
+// Поведение можно подменить через FetchFunc и SendFunc, например чтобы вернуть ошибку
+type MockClient struct {
+	FetchFunc func() ([]Message, error)
+	SendFunc  func(email, message string) error
+	Messages  []Message
+
+	mu sync.Mutex
+	// Sent — успешно отправленные сообщения
+	Sent []Message
+	// FetchCalls и SendCalls — сколько раз вызывались методы
+	FetchCalls int
+	SendCalls  int
+	keys       map[string]bool
+}
+
+// FetchMessages — возвращает подготовленные сообщения
+func (c *MockClient) FetchMessages() ([]Message, error) {
+	c.mu.Lock()
+	c.FetchCalls++
+	fetch, messages := c.FetchFunc, c.Messages
+	c.mu.Unlock()
+
+	if fetch != nil {
+		return fetch()
+	}
+	if messages == nil {
+		return []Message{{Text: "привет"}, {Text: "тестовый пример"}}, nil
+	}
+	return messages, nil
+}
+
+// SendMessage — запоминает сообщение в Sent, если SendFunc не вернул ошибку
+func (c *MockClient) SendMessage(email string, message string) error {
+	c.mu.Lock()
+	c.SendCalls++
+	send := c.SendFunc
+	c.mu.Unlock()
+
+	if send != nil {
+		if err := send(email, message); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Sent = append(c.Sent, Message{Email: email, Text: message})
+	return nil
+}
+
+// SendMessageWithKey — как SendMessage, но повторная отправка с тем же ключом ничего не делает,
+// как у API, которые поддерживают ключи идемпотентности. Ключ занимается ещё до отправки,
+// поэтому повтор, пришедший, пока первая попытка не закончилась, тоже не создаёт дубликата
+func (c *MockClient) SendMessageWithKey(key, email, message string) error {
+	c.mu.Lock()
+	if c.keys[key] {
+		c.SendCalls++
+		c.mu.Unlock()
+		return nil
+	}
+	if c.keys == nil {
+		c.keys = make(map[string]bool)
+	}
+	c.keys[key] = true
+	c.mu.Unlock()
+
+	err := c.SendMessage(email, message)
+	if err != nil {
+		c.mu.Lock()
+		delete(c.keys, key)
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// SentMessages — копия Sent, которую безопасно читать, пока заглушкой пользуются другие горутины
+func (c *MockClient) SentMessages() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Message(nil), c.Sent...)
+}
+
+var (
+	_ Client           = (*MockClient)(nil)
+	_ IdempotentSender = (*MockClient)(nil)
+)
@@ -0,0 +1,256 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// OutboxEntry — сообщение, которое ждёт отправки
+type OutboxEntry struct {
+	// ID — номер в очереди; он же ключ идемпотентности, если клиент их поддерживает
+	ID      string    `json:"id"`
+	Email   string    `json:"email"`
+	Text    string    `json:"text"`
+	Created time.Time `json:"created"`
+	// Attempts — сколько раз уже пробовали отправить; NextAttempt — не раньше какого времени пробовать снова
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// OutboxOptions — настройки Outbox. Нулевые значения заменяются значениями по умолчанию
+type OutboxOptions struct {
+	// Interval — как часто Run пытается отправить очередь. По умолчанию 1 с
+	Interval time.Duration
+	// RetryDelay и MaxRetryDelay — пауза перед повтором растёт как RetryDelay·2ⁿ, но не больше MaxRetryDelay.
+	// По умолчанию 1 с и 1 мин
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	// ShutdownTimeout — сколько Run после отмены ctx отправляет то, что осталось в очереди. По умолчанию 5 с
+	ShutdownTimeout time.Duration
+	// OnError — куда сообщать о неудачных отправках и ошибках записи файла. По умолчанию ошибки отбрасываются
+	OnError func(error)
+	Clock   Clock
+}
+
+// outboxFile — содержимое файла очереди
+type outboxFile struct {
+	NextID  int            `json:"next_id"`
+	Entries []*OutboxEntry `json:"entries"`
+}
+
+// Outbox — очередь исходящих сообщений в файле. Сообщение записывается на диск до отправки
+// и удаляется из очереди, только когда SendMessage вернул nil, поэтому ни сбой API,
+// ни перезапуск программы его не теряют. Неудачные отправки повторяются с растущей паузой.
+// Если клиент реализует IdempotentSender, сообщение отправляется с ключом, и повтор после
+// ответа, который не дошёл, не создаёт дубликата
+type Outbox struct {
+	client Client
+	path   string
+	opts   OutboxOptions
+
+	// sendMu — отправкой занимается один Flush за раз
+	sendMu sync.Mutex
+	mu     sync.Mutex
+	file   outboxFile
+	wake   chan struct{}
+}
+
+// OpenOutbox — открывает очередь в файле path; если файл уже есть, неотправленные сообщения из него подхватываются
+func OpenOutbox(path string, client Client, opts OutboxOptions) (*Outbox, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = time.Second
+	}
+	if opts.MaxRetryDelay <= 0 {
+		opts.MaxRetryDelay = time.Minute
+	}
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = 5 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+
+	o := &Outbox{client: client, path: path, opts: opts, wake: make(chan struct{}, 1)}
+	raw, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(raw, &o.file); err != nil {
+			return nil, fmt.Errorf("client: outbox %s: %w", path, err)
+		}
+	}
+	return o, nil
+}
+
+// Enqueue — ставит сообщение в очередь. Когда Enqueue вернул nil, сообщение уже на диске
+func (o *Outbox) Enqueue(email, text string) (string, error) {
+	o.mu.Lock()
+	o.file.NextID++
+	e := &OutboxEntry{ID: fmt.Sprint(o.file.NextID), Email: email, Text: text, Created: o.opts.Clock.Now()}
+	o.file.Entries = append(o.file.Entries, e)
+	if err := o.saveLocked(); err != nil {
+		o.file.Entries = o.file.Entries[:len(o.file.Entries)-1]
+		o.mu.Unlock()
+		return "", err
+	}
+	o.mu.Unlock()
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return e.ID, nil
+}
+
+// Pending — копия неотправленных сообщений
+func (o *Outbox) Pending() []OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	res := make([]OutboxEntry, len(o.file.Entries))
+	for i, e := range o.file.Entries {
+		res[i] = *e
+	}
+	return res
+}
+
+// Flush — пытается отправить все сообщения, чья пауза истекла, по порядку.
+// Возвращает, сколько отправлено. Ошибки отправки уходят в OnError, а сообщение ждёт следующей попытки
+func (o *Outbox) Flush(ctx context.Context) (int, error) {
+	o.sendMu.Lock()
+	defer o.sendMu.Unlock()
+
+	sent := 0
+	for _, e := range o.due() {
+		if ctx.Err() != nil {
+			break
+		}
+		err := o.send(e)
+
+		o.mu.Lock()
+		if err == nil {
+			o.removeLocked(e.ID)
+		} else {
+			e.Attempts++
+			e.LastError = err.Error()
+			e.NextAttempt = o.opts.Clock.Now().Add(o.retryDelay(e.Attempts))
+		}
+		saveErr := o.saveLocked()
+		o.mu.Unlock()
+
+		if saveErr != nil {
+			return sent, saveErr
+		}
+		if err != nil {
+			o.opts.OnError(fmt.Errorf("client: outbox message %s to %s: %w", e.ID, e.Email, err))
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Run — отправляет очередь сразу после Enqueue и каждые Interval, пока не отменят ctx.
+// Перед выходом делает последнюю попытку, но не дольше ShutdownTimeout: новые отправки после него
+// не начинаются, а уже начатая дожидается ответа, потому что прервать вызов Client нельзя.
+// Что не ушло, остаётся в файле до следующего запуска
+func (o *Outbox) Run(ctx context.Context) error {
+	for {
+		if _, err := o.Flush(ctx); err != nil {
+			o.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			shutdown, cancel := context.WithTimeout(context.Background(), o.opts.ShutdownTimeout)
+			defer cancel()
+			_, err := o.Flush(shutdown)
+			return err
+		case <-o.wake:
+		case <-o.opts.Clock.After(o.opts.Interval):
+		}
+	}
+}
+
+// due — сообщения, которые пора отправлять
+func (o *Outbox) due() []*OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := o.opts.Clock.Now()
+	var res []*OutboxEntry
+	for _, e := range o.file.Entries {
+		if !e.NextAttempt.After(now) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func (o *Outbox) send(e *OutboxEntry) error {
+	if sender, ok := o.client.(IdempotentSender); ok {
+		return sender.SendMessageWithKey("outbox-"+e.ID, e.Email, e.Text)
+	}
+	return o.client.SendMessage(e.Email, e.Text)
+}
+
+// retryDelay — пауза после attempts неудачных попыток
+func (o *Outbox) retryDelay(attempts int) time.Duration {
+	if shift := attempts - 1; shift < 32 {
+		if d := o.opts.RetryDelay << uint(shift); d > 0 && d < o.opts.MaxRetryDelay {
+			return d
+		}
+	}
+	return o.opts.MaxRetryDelay
+}
+
+func (o *Outbox) removeLocked(id string) {
+	for i, e := range o.file.Entries {
+		if e.ID == id {
+			o.file.Entries = append(o.file.Entries[:i], o.file.Entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// saveLocked — записывает очередь в файл; вызывающий должен держать o.mu
+func (o *Outbox) saveLocked() error {
+	return writeFileAtomic(o.path, o.file)
+}
+
+// writeFileAtomic — переписывает файл path целиком через временный файл, чтобы сбой посреди записи
+// не оставил его испорченным
+func writeFileAtomic(path string, v interface{}) error {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
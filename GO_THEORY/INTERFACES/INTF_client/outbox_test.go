@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOutboxRetries(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	fails := 2
+	mock := &MockClient{SendFunc: func(email, message string) error {
+		if fails > 0 {
+			fails--
+			return errTransient
+		}
+		return nil
+	}}
+	var errs []error
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	o, err := OpenOutbox(path, mock, OutboxOptions{RetryDelay: time.Second, Clock: clock, OnError: func(err error) { errs = append(errs, err) }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.Enqueue("bob@example.com", "привет"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, _ := o.Flush(context.Background()); n != 0 || len(errs) != 1 || !errors.Is(errs[0], errTransient) {
+		t.Fatalf("expected failed send; got: %d sent, errors %v", n, errs)
+	}
+	// пауза ещё не истекла — повтора нет
+	if n, _ := o.Flush(context.Background()); n != 0 || mock.SendCalls != 1 {
+		t.Errorf("message must wait for its retry; got: %d sent after %d calls", n, mock.SendCalls)
+	}
+	clock.Advance(time.Second)
+	o.Flush(context.Background())
+	if p := o.Pending(); len(p) != 1 || p[0].Attempts != 2 || !p[0].NextAttempt.Equal(clock.Now().Add(2*time.Second)) {
+		t.Fatalf("expected growing retry delay; got: %+v", p)
+	}
+
+	// очередь переживает перезапуск
+	o, err = OpenOutbox(path, mock, OutboxOptions{Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(2 * time.Second)
+	if n, err := o.Flush(context.Background()); n != 1 || err != nil {
+		t.Errorf("expected message sent after reopening; got: %d, %v", n, err)
+	}
+	if sent := mock.SentMessages(); len(sent) != 1 || sent[0].Text != "привет" {
+		t.Errorf("message must be delivered once; got: %v", sent)
+	}
+	if o, _ := OpenOutbox(path, mock, OutboxOptions{}); len(o.Pending()) != 0 {
+		t.Errorf("acknowledged message must be removed from the file; got: %v", o.Pending())
+	}
+}
+
+func TestOutboxShutdownTimeout(t *testing.T) {
+	// API отвечает медленно: за ShutdownTimeout успевает уйти только часть очереди
+	slow := &MockClient{SendFunc: func(string, string) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}}
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	o, err := OpenOutbox(path, slow, OutboxOptions{ShutdownTimeout: 30 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := o.Enqueue("bob@example.com", "привет"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if err := o.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("final flush must stop after ShutdownTimeout; took %v", elapsed)
+	}
+	if n := len(o.Pending()); n == 0 || n == 10 {
+		t.Errorf("expected part of the queue sent before shutdown; got %d pending", n)
+	}
+}
+
+func TestPipelineShutdown(t *testing.T) {
+	api := &BigAPIClient{}
+	api.Connect()
+	api.SendMessage("bob@example.com", "привет")
+	api.SendMessage("alice@example.com", "добрый день")
+
+	// ответы не уходят: API отвергает отправку
+	var mu sync.Mutex
+	down := true
+	replies := &MockClient{SendFunc: func(email, message string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if down {
+			return errTransient
+		}
+		return nil
+	}}
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	outbox, err := OpenOutbox(path, replies, OutboxOptions{Interval: time.Millisecond, RetryDelay: time.Millisecond, MaxRetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	poller := NewPoller(api, PollerOptions{Interval: time.Millisecond, Handlers: []Handler{
+		func(ctx context.Context, msg Message) error {
+			_, err := outbox.Enqueue(msg.Email, "re: "+msg.Text)
+			return err
+		},
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); poller.Run(ctx) }()
+	go func() { defer wg.Done(); outbox.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(outbox.Pending()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected two queued replies; got: %v", outbox.Pending())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+
+	// после остановки ответы на месте, и следующий запуск их отправляет
+	mu.Lock()
+	down = false
+	mu.Unlock()
+	time.Sleep(2 * time.Millisecond)
+	outbox, err = OpenOutbox(path, replies, OutboxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := outbox.Flush(context.Background()); n != 2 || err != nil {
+		t.Errorf("queued replies must survive shutdown; got: %d, %v", n, err)
+	}
+	if sent := replies.SentMessages(); len(sent) != 2 || sent[0].Text != "re: привет" {
+		t.Errorf("unexpected replies: %v", sent)
+	}
+}
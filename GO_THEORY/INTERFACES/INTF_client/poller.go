@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Handler — обработчик входящего сообщения. Ответы лучше класть в Outbox, а не отправлять сразу:
+// тогда они не потеряются, если API недоступен
+type Handler func(ctx context.Context, msg Message) error
+
+// PollerOptions — настройки Poller. Нулевые значения заменяются значениями по умолчанию
+type PollerOptions struct {
+	// Interval — пауза между запросами FetchMessages. По умолчанию 1 с
+	Interval time.Duration
+	// Handlers — обработчики; каждое новое сообщение получают все по очереди
+	Handlers []Handler
+	// Remember — сколько последних ID помнить, чтобы отсеивать повторы. По умолчанию 1000
+	Remember int
+	// MaxAttempts — сколько раз передавать сообщение обработчикам, которые вернули ошибку.
+	// После стольких неудач сообщение уходит в DeadLetter. По умолчанию 10
+	MaxAttempts int
+	// DeadLetter — куда отдавать сообщения, которые так и не приняли обработчики; err — последняя ошибка.
+	// По умолчанию ошибка уходит в OnError, а сообщение отбрасывается
+	DeadLetter func(msg Message, err error)
+	// OnError — куда сообщать об ошибках FetchMessages, обработчиков и записи файла. По умолчанию ошибки отбрасываются
+	OnError func(error)
+	// Presence — если задан, на время обработки сообщений статус меняется на busy
+	Presence *Presence
+	Clock    Clock
+}
+
+// Poller — забирает сообщения через FetchMessages с заданным интервалом, отсеивает уже виденные
+// по ID и передаёт новые обработчикам. Сообщения без ID не отсеиваются.
+//
+// FetchMessages забирает сообщения из API насовсем, поэтому сообщение, которое не принял
+// хотя бы один обработчик, Poller хранит сам и при следующем опросе передаёт снова —
+// только тем обработчикам, которые вернули ошибку. Виденным оно считается, когда его приняли все
+// или когда оно ушло в DeadLetter. Poller из OpenPoller хранит такие сообщения в файле
+type Poller struct {
+	client Client
+	opts   PollerOptions
+	// path — файл для неразобранных сообщений; пустой у Poller из NewPoller
+	path string
+
+	seen map[string]bool
+	// order — виденные ID в порядке появления, чтобы забывать самые старые
+	order []string
+	// retry — сообщения, которые ещё не приняли все обработчики
+	retry []*pendingMessage
+}
+
+// pendingMessage — сообщение и номера обработчиков, которым его ещё нужно передать
+type pendingMessage struct {
+	Message  Message `json:"message"`
+	Handlers []int   `json:"handlers"`
+	// Attempts — сколько раз обработчики уже вернули ошибку
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// pollerFile — содержимое файла Poller
+type pollerFile struct {
+	Pending []*pendingMessage `json:"pending"`
+}
+
+// NewPoller — создаёт Poller над client. Неразобранные сообщения хранятся только в памяти
+func NewPoller(client Client, opts PollerOptions) *Poller {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.Remember <= 0 {
+		opts.Remember = 1000
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 10
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	if opts.DeadLetter == nil {
+		onError := opts.OnError
+		opts.DeadLetter = func(msg Message, err error) { onError(err) }
+	}
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+	return &Poller{client: client, opts: opts, seen: make(map[string]bool)}
+}
+
+// OpenPoller — создаёт Poller, который хранит неразобранные сообщения в файле path так же, как Outbox
+// хранит очередь: новые сообщения записываются туда сразу после FetchMessages, ещё до обработки,
+// поэтому их не теряют ни ошибки обработчиков, ни перезапуск программы. Если файл уже есть,
+// сообщения из него получат первый Poll. Handlers должны быть те же и в том же порядке
+func OpenPoller(path string, client Client, opts PollerOptions) (*Poller, error) {
+	p := NewPoller(client, opts)
+	p.path = path
+	raw, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return p, nil
+	case err != nil:
+		return nil, err
+	}
+	var file pollerFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("client: poller %s: %w", path, err)
+	}
+	for _, pm := range file.Pending {
+		for _, i := range pm.Handlers {
+			if i < 0 || i >= len(p.opts.Handlers) {
+				return nil, fmt.Errorf("client: poller %s: message %s: no handler %d", path, pm.Message.ID, i)
+			}
+		}
+	}
+	p.retry = file.Pending
+	return p, nil
+}
+
+// Run — опрашивает API, пока не отменят ctx. Уже полученная пачка сообщений обрабатывается
+// до конца и после отмены, чтобы сообщения не терялись; то, что обработчики так и не приняли,
+// после выхода можно забрать через Pending, а у Poller из OpenPoller оно остаётся в файле
+// до следующего запуска. Run и Poll нельзя вызывать одновременно
+func (p *Poller) Run(ctx context.Context) error {
+	for {
+		if _, err := p.Poll(ctx); err != nil {
+			p.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.opts.Clock.After(p.opts.Interval):
+		}
+	}
+}
+
+// Poll — один запрос: сначала повторяет сообщения, которые не приняли обработчики в прошлый раз,
+// затем забирает новые и передаёт их обработчикам. Возвращает, сколько сообщений передано.
+// Ошибки обработчиков уходят в OnError, а сообщение остаётся для следующего Poll,
+// пока не исчерпает MaxAttempts
+func (p *Poller) Poll(ctx context.Context) (int, error) {
+	work := p.retry
+	queued := make(map[string]bool, len(work))
+	for _, pm := range work {
+		queued[pm.Message.ID] = true
+	}
+
+	messages, err := p.client.FetchMessages()
+	fresh := false
+	for _, msg := range messages {
+		if msg.ID != "" && (p.seen[msg.ID] || queued[msg.ID]) {
+			continue
+		}
+		queued[msg.ID] = true
+		all := make([]int, len(p.opts.Handlers))
+		for i := range all {
+			all[i] = i
+		}
+		work = append(work, &pendingMessage{Message: msg, Handlers: all})
+		fresh = true
+	}
+	// API отдал новые сообщения насовсем, поэтому они попадают в файл до обработки
+	p.retry = work
+	if fresh {
+		p.save()
+	}
+
+	if len(work) > 0 && p.opts.Presence != nil {
+		p.opts.Presence.begin()
+		defer p.opts.Presence.end()
+	}
+	var next []*pendingMessage
+	for _, pm := range work {
+		var (
+			failed []int
+			last   error
+		)
+		for _, i := range pm.Handlers {
+			if err := p.opts.Handlers[i](ctx, pm.Message); err != nil {
+				p.opts.OnError(err)
+				failed, last = append(failed, i), err
+			}
+		}
+		if len(failed) == 0 {
+			p.remember(pm.Message.ID)
+			continue
+		}
+		pm.Handlers, pm.Attempts, pm.LastError = failed, pm.Attempts+1, last.Error()
+		if pm.Attempts >= p.opts.MaxAttempts {
+			// дубликат, который API отдаст позже, тоже не нужно обрабатывать
+			p.remember(pm.Message.ID)
+			p.opts.DeadLetter(pm.Message, fmt.Errorf("client: message %s: %d attempts failed: %w", pm.Message.ID, pm.Attempts, last))
+			continue
+		}
+		next = append(next, pm)
+	}
+	p.retry = next
+	if len(work) > 0 {
+		p.save()
+	}
+	return len(work), err
+}
+
+// Pending — сообщения, которые не приняли обработчики и которые Poll передаст снова
+func (p *Poller) Pending() []Message {
+	res := make([]Message, len(p.retry))
+	for i, pm := range p.retry {
+		res[i] = pm.Message
+	}
+	return res
+}
+
+// save — записывает неразобранные сообщения в файл, если он есть. Ошибка записи уходит в OnError:
+// сообщения уже забраны из API, и обработать их лучше, чем бросить
+func (p *Poller) save() {
+	if p.path == "" {
+		return
+	}
+	if err := writeFileAtomic(p.path, pollerFile{Pending: p.retry}); err != nil {
+		p.opts.OnError(fmt.Errorf("client: poller %s: %w", p.path, err))
+	}
+}
+
+// remember — запоминает ID обработанного сообщения
+func (p *Poller) remember(id string) {
+	if id == "" || p.seen[id] {
+		return
+	}
+	p.seen[id] = true
+	p.order = append(p.order, id)
+	if len(p.order) > p.opts.Remember {
+		delete(p.seen, p.order[0])
+		p.order = p.order[1:]
+	}
+}
@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPollerDeduplicates(t *testing.T) {
+	batches := [][]Message{
+		{{ID: "1", Text: "привет"}, {ID: "2", Text: "как дела"}},
+		{{ID: "2", Text: "как дела"}, {ID: "3", Text: "пока"}, {Text: "без ID"}},
+	}
+	mock := &MockClient{FetchFunc: func() ([]Message, error) {
+		if len(batches) == 0 {
+			return nil, errTransient
+		}
+		b := batches[0]
+		batches = batches[1:]
+		return b, nil
+	}}
+	var got []string
+	p := NewPoller(mock, PollerOptions{Handlers: []Handler{
+		func(ctx context.Context, msg Message) error {
+			got = append(got, msg.Text)
+			return nil
+		},
+	}})
+
+	for _, expected := range []int{2, 2} {
+		if n, err := p.Poll(context.Background()); err != nil || n != expected {
+			t.Errorf("expected %d new messages; got: %d, %v", expected, n, err)
+		}
+	}
+	if _, err := p.Poll(context.Background()); err != errTransient {
+		t.Errorf("expected fetch error; got: %v", err)
+	}
+	if len(got) != 4 || got[2] != "пока" || got[3] != "без ID" {
+		t.Errorf("unexpected handled messages: %v", got)
+	}
+}
+
+func TestPollerRetriesFailedHandlers(t *testing.T) {
+	batches := [][]Message{
+		{{ID: "1", Text: "привет"}, {ID: "2", Text: "пока"}},
+		{{ID: "1", Text: "привет"}},
+	}
+	mock := &MockClient{FetchFunc: func() ([]Message, error) {
+		if len(batches) == 0 {
+			return nil, nil
+		}
+		b := batches[0]
+		batches = batches[1:]
+		return b, nil
+	}}
+	errFull := errors.New("outbox is full")
+	var logged, replied []string
+	failures := 1
+	var errs []error
+	p := NewPoller(mock, PollerOptions{
+		OnError: func(err error) { errs = append(errs, err) },
+		Handlers: []Handler{
+			func(ctx context.Context, msg Message) error {
+				logged = append(logged, msg.ID)
+				return nil
+			},
+			func(ctx context.Context, msg Message) error {
+				if msg.ID == "1" && failures > 0 {
+					failures--
+					return errFull
+				}
+				replied = append(replied, msg.ID)
+				return nil
+			},
+		},
+	})
+
+	if n, err := p.Poll(context.Background()); n != 2 || err != nil {
+		t.Fatalf("expected 2 messages; got: %d, %v", n, err)
+	}
+	if pending := p.Pending(); len(pending) != 1 || pending[0].ID != "1" || len(errs) != 1 || !errors.Is(errs[0], errFull) {
+		t.Fatalf("failed message must be kept; got: %v, errors %v", pending, errs)
+	}
+	// сообщение повторяется, хотя API его больше не отдаёт, и только для обработчика, который ошибся;
+	// пришедший снова дубликат не передаётся второй раз
+	if n, err := p.Poll(context.Background()); n != 1 || err != nil {
+		t.Fatalf("expected the retry; got: %d, %v", n, err)
+	}
+	if len(p.Pending()) != 0 || !reflect.DeepEqual(logged, []string{"1", "2"}) || !reflect.DeepEqual(replied, []string{"2", "1"}) {
+		t.Errorf("unexpected deliveries: logged %v, replied %v", logged, replied)
+	}
+	if n, _ := p.Poll(context.Background()); n != 0 {
+		t.Errorf("handled message must be deduplicated; got %d", n)
+	}
+}
+
+func TestPollerDeadLetter(t *testing.T) {
+	// API отдаёт одно и то же сообщение при каждом запросе
+	mock := &MockClient{Messages: []Message{{ID: "1", Text: "привет"}}}
+	errBroken := errors.New("handler is broken")
+	calls := 0
+	var dead []Message
+	var deadErr error
+	p := NewPoller(mock, PollerOptions{
+		MaxAttempts: 2,
+		DeadLetter:  func(msg Message, err error) { dead, deadErr = append(dead, msg), err },
+		Handlers: []Handler{
+			func(ctx context.Context, msg Message) error {
+				calls++
+				return errBroken
+			},
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		p.Poll(context.Background())
+	}
+	if len(dead) != 1 || dead[0].ID != "1" || !errors.Is(deadErr, errBroken) {
+		t.Errorf("expected the message in dead letters after 2 attempts; got: %v, %v", dead, deadErr)
+	}
+	if calls != 2 || len(p.Pending()) != 0 {
+		t.Errorf("dead letter must not be retried; got %d calls, pending %v", calls, p.Pending())
+	}
+}
+
+func TestPollerSurvivesRestart(t *testing.T) {
+	var once sync.Once
+	mock := &MockClient{FetchFunc: func() ([]Message, error) {
+		var res []Message
+		once.Do(func() { res = []Message{{ID: "1", Text: "привет"}} })
+		return res, nil
+	}}
+	var mu sync.Mutex
+	down, failures := true, 0
+	var delivered []string
+	handlers := []Handler{
+		func(ctx context.Context, msg Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if down {
+				failures++
+				return errTransient
+			}
+			delivered = append(delivered, msg.Text)
+			return nil
+		},
+	}
+	path := filepath.Join(t.TempDir(), "poller.json")
+	p, err := OpenPoller(path, mock, PollerOptions{Interval: time.Millisecond, MaxAttempts: 1000, Handlers: handlers})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run останавливают, пока обработчик ещё не принял сообщение
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := failures
+		mu.Unlock()
+		if n >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected failing retries")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// новый Poller подхватывает сообщение из файла, хотя API его больше не отдаёт
+	mu.Lock()
+	down = false
+	mu.Unlock()
+	p, err = OpenPoller(path, mock, PollerOptions{Handlers: handlers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := p.Poll(context.Background()); n != 1 || err != nil {
+		t.Fatalf("expected the saved message; got: %d, %v", n, err)
+	}
+	if !reflect.DeepEqual(delivered, []string{"привет"}) {
+		t.Errorf("message must be delivered after restart; got: %v", delivered)
+	}
+	if p, _ := OpenPoller(path, mock, PollerOptions{Handlers: handlers}); len(p.Pending()) != 0 {
+		t.Errorf("delivered message must be removed from the file; got: %v", p.Pending())
+	}
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrInvalidTransition — такой смены статуса не бывает, например offline → busy
+var ErrInvalidTransition = errors.New("client: invalid status transition")
+
+// Status — статус присутствия, который видят собеседники
+type Status int
+
+const (
+	Offline Status = iota
+	Online
+	Busy
+	Away
+)
+
+func (s Status) String() string {
+	switch s {
+	case Offline:
+		return "offline"
+	case Online:
+		return "online"
+	case Busy:
+		return "busy"
+	case Away:
+		return "away"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// transitions — допустимые смены статуса. Из offline можно выйти только в online,
+// уйти в offline можно из любого статуса
+var transitions = map[Status][]Status{
+	Offline: {Online},
+	Online:  {Busy, Away, Offline},
+	Busy:    {Online, Away, Offline},
+	Away:    {Online, Busy, Offline},
+}
+
+// CanTransition — допустима ли смена статуса from → to. Остаться в том же статусе можно всегда
+func CanTransition(from, to Status) bool {
+	if from == to {
+		_, ok := transitions[from]
+		return ok
+	}
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError — недопустимая смена статуса
+type TransitionError struct {
+	From, To Status
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("client: invalid status transition %v → %v", e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// PresenceOptions — настройки Presence. Нулевые значения заменяются значениями по умолчанию
+type PresenceOptions struct {
+	// Heartbeat — как часто Run повторяет текущий статус. По умолчанию 30 с
+	Heartbeat time.Duration
+	// TTL — сколько API помнит статус без повторов; если за это время ни одна отправка не удалась,
+	// собеседники видят offline. По умолчанию три Heartbeat
+	TTL time.Duration
+	// OnError — куда сообщать о неудачных отправках. По умолчанию ошибки отбрасываются
+	OnError func(error)
+	Clock   Clock
+}
+
+// Presence — сообщает API наш статус через SendStatus и следит, чтобы он не истёк:
+// Run повторяет статус каждые Heartbeat. Смена статуса проверяется по таблице transitions.
+//
+// Пока Poller с этим Presence обрабатывает сообщения, поверх выбранного статуса
+// выставляется busy, а после обработки выбранный статус возвращается
+type Presence struct {
+	sender StatusSender
+	opts   PresenceOptions
+
+	mu     sync.Mutex
+	status Status
+	// busy — сколько обработок идёт сейчас
+	busy int
+	// sent — последний отправленный статус; ackedAt — когда API последний раз принял статус
+	sent    Status
+	ackedAt time.Time
+}
+
+// NewPresence — создаёт Presence в статусе offline; подходят *BigAPIClient и *Managed,
+// который отправляет статус всегда через одно и то же подключение
+func NewPresence(sender StatusSender, opts PresenceOptions) *Presence {
+	if opts.Heartbeat <= 0 {
+		opts.Heartbeat = 30 * time.Second
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 3 * opts.Heartbeat
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+	return &Presence{sender: sender, opts: opts}
+}
+
+// Set — меняет выбранный статус и сообщает его API. Недопустимая смена завершается TransitionError
+// и ничего не меняет. Если API не ответил, статус всё равно меняется, и его повторит следующий Heartbeat
+func (p *Presence) Set(status Status) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !CanTransition(p.status, status) {
+		return &TransitionError{From: p.status, To: status}
+	}
+	p.status = status
+	return p.publishLocked()
+}
+
+// Status — статус, который сейчас видят собеседники: offline, если он истёк
+func (p *Presence) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.expiredLocked() {
+		return Offline
+	}
+	return p.sent
+}
+
+// Expired — API не принимал статус дольше TTL
+func (p *Presence) Expired() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.expiredLocked()
+}
+
+// Run — выставляет online и повторяет статус каждые Heartbeat, пока не отменят ctx; перед выходом выставляет offline
+func (p *Presence) Run(ctx context.Context) error {
+	if err := p.Set(Online); err != nil {
+		p.opts.OnError(err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return p.Set(Offline)
+		case <-p.opts.Clock.After(p.opts.Heartbeat):
+			p.mu.Lock()
+			err := p.publishLocked()
+			p.mu.Unlock()
+			if err != nil {
+				p.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// begin — началась обработка сообщений. В offline статус не трогаем: из него нельзя сразу в busy
+func (p *Presence) begin() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.busy++
+	if p.busy == 1 && p.status != Offline {
+		if err := p.publishLocked(); err != nil {
+			p.opts.OnError(err)
+		}
+	}
+}
+
+// end — обработка закончилась; когда закончились все, возвращается выбранный статус
+func (p *Presence) end() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.busy--
+	if p.busy == 0 && p.status != Offline {
+		if err := p.publishLocked(); err != nil {
+			p.opts.OnError(err)
+		}
+	}
+}
+
+// effectiveLocked — выбранный статус с учётом идущих обработок
+func (p *Presence) effectiveLocked() Status {
+	if p.busy > 0 && p.status != Offline {
+		return Busy
+	}
+	return p.status
+}
+
+// publishLocked — отправляет статус. Отправка идёт под p.mu, чтобы статусы доходили в том порядке,
+// в котором менялись; вызывающий должен держать p.mu
+func (p *Presence) publishLocked() error {
+	status := p.effectiveLocked()
+	if err := p.sender.SendStatus(status.String()); err != nil {
+		return fmt.Errorf("client: send status %v: %w", status, err)
+	}
+	p.sent, p.ackedAt = status, p.opts.Clock.Now()
+	return nil
+}
+
+func (p *Presence) expiredLocked() bool {
+	return p.sent != Offline && !p.opts.Clock.Now().Before(p.ackedAt.Add(p.opts.TTL))
+}
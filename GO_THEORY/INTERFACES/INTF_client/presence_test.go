@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// statusLog — StatusSender, который запоминает отправленные статусы
+type statusLog struct {
+	mu   sync.Mutex
+	sent []string
+	err  error
+}
+
+func (s *statusLog) SendStatus(status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, status)
+	return nil
+}
+
+func (s *statusLog) get() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.sent...)
+}
+
+func (s *statusLog) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func TestPresenceTransitions(t *testing.T) {
+	statuses := &statusLog{}
+	p := NewPresence(statuses, PresenceOptions{Clock: &fakeClock{}})
+
+	err := p.Set(Busy)
+	var te *TransitionError
+	if !errors.Is(err, ErrInvalidTransition) || !errors.As(err, &te) || te.From != Offline || te.To != Busy {
+		t.Errorf("expected offline → busy to be rejected; got: %v", err)
+	}
+	for _, s := range []Status{Online, Away, Busy, Online, Offline} {
+		if err := p.Set(s); err != nil {
+			t.Fatal(err)
+		}
+		if p.Status() != s {
+			t.Errorf("expected %v; got: %v", s, p.Status())
+		}
+	}
+	expected := []string{"online", "away", "busy", "online", "offline"}
+	if got := statuses.get(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v; got: %v", expected, got)
+	}
+}
+
+func TestPresenceTTL(t *testing.T) {
+	clock := &fakeClock{}
+	statuses := &statusLog{}
+	p := NewPresence(statuses, PresenceOptions{Heartbeat: 10 * time.Second, Clock: clock})
+	p.Set(Online)
+
+	clock.Advance(29 * time.Second)
+	if p.Expired() || p.Status() != Online {
+		t.Fatalf("status must live for TTL; got: %v", p.Status())
+	}
+	statuses.fail(errTransient)
+	if err := p.Set(Away); !errors.Is(err, errTransient) {
+		t.Errorf("expected send error; got: %v", err)
+	}
+	clock.Advance(time.Second)
+	if !p.Expired() || p.Status() != Offline {
+		t.Errorf("status must expire after TTL without heartbeats; got: %v", p.Status())
+	}
+
+	statuses.fail(nil)
+	if err := p.Set(Away); err != nil || p.Status() != Away {
+		t.Errorf("expected away after API recovered; got: %v, %v", p.Status(), err)
+	}
+}
+
+func TestPresenceRun(t *testing.T) {
+	statuses := &statusLog{}
+	p := NewPresence(statuses, PresenceOptions{Heartbeat: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(statuses.get()) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected heartbeats; got: %v", statuses.get())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	sent := statuses.get()
+	if sent[0] != "online" || sent[1] != "online" || sent[len(sent)-1] != "offline" {
+		t.Errorf("expected online heartbeats and offline at the end; got: %v", sent)
+	}
+}
+
+func TestPresenceBusyWhileHandling(t *testing.T) {
+	statuses := &statusLog{}
+	p := NewPresence(statuses, PresenceOptions{})
+	p.Set(Online)
+	p.Set(Away)
+
+	mock := &MockClient{Messages: []Message{{ID: "1", Text: "привет"}, {ID: "2", Text: "пока"}}}
+	var during []Status
+	poller := NewPoller(mock, PollerOptions{Presence: p, Handlers: []Handler{
+		func(ctx context.Context, msg Message) error {
+			during = append(during, p.Status())
+			return nil
+		},
+	}})
+
+	poller.Poll(context.Background())
+	if !reflect.DeepEqual(during, []Status{Busy, Busy}) {
+		t.Errorf("expected busy while handling; got: %v", during)
+	}
+	if p.Status() != Away {
+		t.Errorf("expected chosen status back after handling; got: %v", p.Status())
+	}
+	// повторы отсеяны — обрабатывать нечего, и статус не дёргается
+	poller.Poll(context.Background())
+	expected := []string{"online", "away", "busy", "away"}
+	if got := statuses.get(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v; got: %v", expected, got)
+	}
+}
+
+func TestPresenceOnManaged(t *testing.T) {
+	stats := &connStats{}
+	var conns []*fakeConn
+	m := NewManaged(context.Background(), ManagedOptions{
+		Size:           2,
+		Dial:           newFakeDial(stats, 0, &conns),
+		HealthInterval: time.Millisecond,
+	})
+	defer m.Close()
+	p := NewPresence(m, PresenceOptions{})
+
+	// пул уже держит подключения, а статус всё равно идёт через одно отдельное
+	if _, err := m.FetchMessages(); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []Status{Online, Away, Busy, Away} {
+		if err := p.Set(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	var statuses []string
+	for _, c := range conns {
+		if s := c.status(); s != "" {
+			statuses = append(statuses, s)
+		}
+	}
+	if !reflect.DeepEqual(statuses, []string{"away"}) || p.Status() != Away {
+		t.Errorf("expected away on a single connection; got: %v, presence %v", statuses, p.Status())
+	}
+}
@@ -0,0 +1,348 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCircuitOpen — после череды ошибок вызовы временно не доходят до API
+	ErrCircuitOpen = errors.New("client: circuit open")
+	// ErrTimeout — API не ответил за отведённое время
+	ErrTimeout = errors.New("client: call timed out")
+	// ErrOutcomeUnknown — отправка без ключа идемпотентности не уложилась в Timeout и продолжается в фоне:
+	// сообщение может дойти, а может и нет. Повторять такую отправку вслепую нельзя — получится дубликат
+	ErrOutcomeUnknown = errors.New("client: send timed out, outcome unknown")
+)
+
+// IdempotentSender — клиент, который принимает ключ идемпотентности:
+// повторная отправка с тем же ключом не создаёт второго сообщения
+type IdempotentSender interface {
+	SendMessageWithKey(key, email, message string) error
+}
+
+// Clock — источник времени. В тестах его подменяют, чтобы не ждать пауз между попытками
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// Rand — источник случайности для разброса пауз и ключей идемпотентности; подходит *rand.Rand
+type Rand interface {
+	Float64() float64
+	Int63() int64
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// lockedRand — Rand, которым можно пользоваться из нескольких горутин
+type lockedRand struct {
+	mu  sync.Mutex
+	rnd Rand
+}
+
+func (r *lockedRand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rnd.Float64()
+}
+
+func (r *lockedRand) Int63() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rnd.Int63()
+}
+
+// Options — настройки Resilient. Нулевые значения заменяются значениями по умолчанию
+type Options struct {
+	// MaxAttempts — сколько раз пробовать вызов, считая первый. По умолчанию 3
+	MaxAttempts int
+	// BaseDelay и MaxDelay — пауза перед повтором растёт как BaseDelay·2ⁿ, но не больше MaxDelay.
+	// Сама пауза выбирается случайно от нуля до этой границы. По умолчанию 100 мс и 5 с
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Timeout — ограничение на одну попытку. Ноль — без ограничения
+	Timeout time.Duration
+	// FailureThreshold — после стольких ошибок подряд вызовы перестают доходить до API на Cooldown.
+	// По умолчанию 5 ошибок и 30 с
+	FailureThreshold int
+	Cooldown         time.Duration
+	// Retryable — стоит ли повторять вызов после ошибки. По умолчанию повторяются все ошибки
+	Retryable func(error) bool
+
+	Clock Clock
+	Rand  Rand
+}
+
+// BreakerState — состояние предохранителя
+type BreakerState int
+
+const (
+	// Closed — вызовы проходят как обычно
+	Closed BreakerState = iota
+	// Open — вызовы сразу завершаются ErrCircuitOpen
+	Open
+	// HalfOpen — пауза истекла, один пробный вызов решит, закрыть предохранитель или снова открыть
+	HalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// Resilient — обёртка над Client, которая переживает временные сбои API:
+// повторяет вызовы с растущей случайной паузой, ограничивает время попытки
+// и перестаёт дёргать API, пока тот не придёт в себя.
+//
+// SendMessage повторяется, только если обёрнутый клиент реализует IdempotentSender, как *BigAPIClient
+// и MockClient: все попытки одного вызова идут с одним ключом, и сообщение не задвоится, даже если
+// попытка, которую посчитали неудачной, на самом деле дошла. Иначе SendMessage пробуется один раз,
+// а если он не уложился в Timeout, возвращается ErrOutcomeUnknown
+type Resilient struct {
+	client Client
+	opts   Options
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	// probing — пробный вызов в состоянии HalfOpen уже идёт
+	probing bool
+	// late — попытки FetchMessages, которые не уложились в Timeout и ещё идут. FetchMessages забирает
+	// сообщения из API, поэтому бросать такую попытку нельзя: её результат достанется следующей попытке
+	late []chan fetchResult
+}
+
+// fetchResult — итог одного вызова FetchMessages
+type fetchResult struct {
+	messages []Message
+	err      error
+}
+
+// NewResilient — оборачивает client
+func NewResilient(client Client, opts Options) *Resilient {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = 100 * time.Millisecond
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = 5 * time.Second
+	}
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 5
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 30 * time.Second
+	}
+	if opts.Retryable == nil {
+		opts.Retryable = func(error) bool { return true }
+	}
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+	if opts.Rand == nil {
+		opts.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	opts.Rand = &lockedRand{rnd: opts.Rand}
+	return &Resilient{client: client, opts: opts}
+}
+
+// FetchMessages — FetchMessages обёрнутого клиента с повторами.
+// Если попытка не уложилась в Timeout, новая не начинается, пока не закончится старая:
+// следующая попытка, в том числе при следующем вызове FetchMessages, дожидается её результата
+func (r *Resilient) FetchMessages() ([]Message, error) {
+	return retry(r, r.opts.MaxAttempts, r.fetch)
+}
+
+// fetch — одна попытка FetchMessages: продолжает отложенную, если такая есть, иначе начинает новую
+func (r *Resilient) fetch() ([]Message, error) {
+	if r.opts.Timeout <= 0 {
+		return r.client.FetchMessages()
+	}
+	r.mu.Lock()
+	var attempt chan fetchResult
+	if len(r.late) > 0 {
+		attempt, r.late = r.late[0], r.late[1:]
+	}
+	r.mu.Unlock()
+	if attempt == nil {
+		attempt = make(chan fetchResult, 1)
+		go func() {
+			messages, err := r.client.FetchMessages()
+			attempt <- fetchResult{messages, err}
+		}()
+	}
+
+	select {
+	case res := <-attempt:
+		return res.messages, res.err
+	case <-r.opts.Clock.After(r.opts.Timeout):
+		r.mu.Lock()
+		r.late = append(r.late, attempt)
+		r.mu.Unlock()
+		return nil, ErrTimeout
+	}
+}
+
+// SendMessage — SendMessage обёрнутого клиента; с ключом идемпотентности и повторами, если клиент их поддерживает
+func (r *Resilient) SendMessage(email string, message string) error {
+	sender, ok := r.client.(IdempotentSender)
+	if !ok {
+		_, err := retry(r, 1, func() (struct{}, error) {
+			_, err := withTimeout(r, func() (struct{}, error) { return struct{}{}, r.client.SendMessage(email, message) })
+			if err == ErrTimeout {
+				// попытка не отменена и может дойти: это не обычный таймаут, который безопасно повторить
+				err = ErrOutcomeUnknown
+			}
+			return struct{}{}, err
+		})
+		return err
+	}
+	key := r.newKey()
+	_, err := retry(r, r.opts.MaxAttempts, func() (struct{}, error) {
+		return withTimeout(r, func() (struct{}, error) { return struct{}{}, sender.SendMessageWithKey(key, email, message) })
+	})
+	return err
+}
+
+// State — текущее состояние предохранителя
+func (r *Resilient) State() BreakerState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stateLocked()
+}
+
+// newKey — случайный ключ идемпотентности
+func (r *Resilient) newKey() string {
+	return fmt.Sprintf("%016x%016x", r.opts.Rand.Int63(), r.opts.Rand.Int63())
+}
+
+// retry — вызывает call до attempts раз, пока он не удастся. Время попытки ограничивает сам call
+func retry[T any](r *Resilient, attempts int, call func() (T, error)) (T, error) {
+	var (
+		zero T
+		err  error
+	)
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			<-r.opts.Clock.After(r.backoff(attempt))
+		}
+		allowed, probe := r.allow()
+		if !allowed {
+			if err == nil {
+				return zero, ErrCircuitOpen
+			}
+			return zero, fmt.Errorf("%w (last error: %v)", ErrCircuitOpen, err)
+		}
+		var res T
+		res, err = call()
+		r.record(err, probe)
+		if err == nil {
+			return res, nil
+		}
+		if !r.opts.Retryable(err) {
+			return zero, err
+		}
+	}
+	if attempts > 1 {
+		return zero, fmt.Errorf("client: %d attempts failed: %w", attempts, err)
+	}
+	return zero, err
+}
+
+// backoff — пауза перед попыткой attempt (с единицы): случайная, до BaseDelay·2^(attempt-1), но не больше MaxDelay
+func (r *Resilient) backoff(attempt int) time.Duration {
+	limit := r.opts.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if d := r.opts.BaseDelay << uint(shift); d > 0 && d < limit {
+			limit = d
+		}
+	}
+	return time.Duration(r.opts.Rand.Float64() * float64(limit))
+}
+
+// withTimeout — одна попытка с ограничением времени. Если время вышло, попытка продолжается в фоне,
+// потому что прервать вызов Client нельзя, а её результат отбрасывается. Поэтому повторять после
+// withTimeout можно только вызовы вроде SendMessageWithKey; для остальных ErrTimeout надо заменить на ErrOutcomeUnknown
+func withTimeout[T any](r *Resilient, call func() (T, error)) (T, error) {
+	if r.opts.Timeout <= 0 {
+		return call()
+	}
+	type result struct {
+		val T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		val, err := call()
+		done <- result{val, err}
+	}()
+	select {
+	case res := <-done:
+		return res.val, res.err
+	case <-r.opts.Clock.After(r.opts.Timeout):
+		var zero T
+		return zero, ErrTimeout
+	}
+}
+
+// allow — можно ли сейчас обратиться к API; probe — это пробный вызов после паузы
+func (r *Resilient) allow() (allowed, probe bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch r.stateLocked() {
+	case Open:
+		return false, false
+	case HalfOpen:
+		if r.probing {
+			return false, false
+		}
+		r.probing = true
+		return true, true
+	}
+	return true, false
+}
+
+// record — учитывает исход попытки
+func (r *Resilient) record(err error, probe bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if probe {
+		r.probing = false
+	}
+	if err == nil {
+		r.state, r.failures = Closed, 0
+		return
+	}
+	r.failures++
+	if probe || r.failures >= r.opts.FailureThreshold {
+		r.state, r.openedAt = Open, r.opts.Clock.Now()
+	}
+}
+
+// stateLocked — состояние с учётом истёкшей паузы; вызывающий должен держать r.mu
+func (r *Resilient) stateLocked() BreakerState {
+	if r.state == Open && !r.opts.Clock.Now().Before(r.openedAt.Add(r.opts.Cooldown)) {
+		return HalfOpen
+	}
+	return r.state
+}
+
+var _ Client = (*Resilient)(nil)
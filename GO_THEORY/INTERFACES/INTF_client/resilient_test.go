@@ -0,0 +1,266 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock — часы, которые не ждут: After сразу срабатывает и переводит время вперёд
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// fixedRand — «случайность», которая всегда выбирает середину
+type fixedRand struct{ n int64 }
+
+func (r *fixedRand) Float64() float64 { return 0.5 }
+func (r *fixedRand) Int63() int64     { r.n++; return r.n }
+
+var errTransient = errors.New("503 service unavailable")
+
+// failing — FetchFunc, который первые n вызовов возвращает errTransient
+func failing(n int) func() ([]Message, error) {
+	var mu sync.Mutex
+	return func() ([]Message, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if n > 0 {
+			n--
+			return nil, errTransient
+		}
+		return []Message{{ID: "1", Text: "привет"}}, nil
+	}
+}
+
+func TestResilientRetries(t *testing.T) {
+	clock := &fakeClock{}
+	mock := &MockClient{FetchFunc: failing(3)}
+	r := NewResilient(mock, Options{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond, Clock: clock, Rand: &fixedRand{}})
+
+	msgs, err := r.FetchMessages()
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("expected success after retries; got: %v, %v", msgs, err)
+	}
+	expected := []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 150 * time.Millisecond}
+	if len(clock.sleeps) != len(expected) {
+		t.Fatalf("expected pauses %v; got: %v", expected, clock.sleeps)
+	}
+	for i := range expected {
+		if clock.sleeps[i] != expected[i] {
+			t.Errorf("pause %d: expected %v; got: %v", i, expected[i], clock.sleeps[i])
+		}
+	}
+
+	mock.FetchFunc = failing(10)
+	if _, err := r.FetchMessages(); !errors.Is(err, errTransient) || mock.FetchCalls != 4+4 {
+		t.Errorf("expected last error after 4 attempts; got: %v after %d calls", err, mock.FetchCalls)
+	}
+
+	permanent := errors.New("401 unauthorized")
+	mock = &MockClient{FetchFunc: func() ([]Message, error) { return nil, permanent }}
+	r = NewResilient(mock, Options{Clock: clock, Rand: &fixedRand{}, Retryable: func(err error) bool { return err != permanent }})
+	if _, err := r.FetchMessages(); err != permanent || mock.FetchCalls != 1 {
+		t.Errorf("permanent error must not be retried; got: %v after %d calls", err, mock.FetchCalls)
+	}
+}
+
+func TestResilientCircuitBreaker(t *testing.T) {
+	clock := &fakeClock{}
+	mock := &MockClient{FetchFunc: failing(3)}
+	r := NewResilient(mock, Options{MaxAttempts: 1, FailureThreshold: 2, Cooldown: time.Minute, Clock: clock, Rand: &fixedRand{}})
+
+	r.FetchMessages()
+	if r.State() != Closed {
+		t.Fatalf("one failure must not open the circuit")
+	}
+	r.FetchMessages()
+	if r.State() != Open {
+		t.Fatalf("expected open circuit; got: %v", r.State())
+	}
+	if _, err := r.FetchMessages(); !errors.Is(err, ErrCircuitOpen) || mock.FetchCalls != 2 {
+		t.Errorf("open circuit must not reach the API; got: %v after %d calls", err, mock.FetchCalls)
+	}
+
+	clock.Advance(time.Minute)
+	if r.State() != HalfOpen {
+		t.Fatalf("expected half-open circuit; got: %v", r.State())
+	}
+	// пробный вызов неудачен — снова пауза
+	r.FetchMessages()
+	if r.State() != Open {
+		t.Fatalf("failed probe must reopen the circuit; got: %v", r.State())
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := r.FetchMessages(); err != nil || r.State() != Closed {
+		t.Errorf("successful probe must close the circuit; got: %v, %v", err, r.State())
+	}
+}
+
+func TestResilientTimeoutAndIdempotency(t *testing.T) {
+	release := make(chan struct{})
+	var once sync.Once
+	mock := &MockClient{}
+	mock.SendFunc = func(email, message string) error {
+		// первая попытка зависает, но в итоге сообщение доходит
+		blocked := false
+		once.Do(func() { blocked = true })
+		if blocked {
+			<-release
+		}
+		return nil
+	}
+	// здесь часы настоящие: поддельные сработали бы раньше, чем успеет ответить и вторая попытка
+	r := NewResilient(mock, Options{MaxAttempts: 3, Timeout: 50 * time.Millisecond, BaseDelay: time.Millisecond, Rand: &fixedRand{}})
+
+	if err := r.SendMessage("bob@example.com", "привет"); err != nil {
+		t.Fatalf("retry with the same key must succeed; got: %v", err)
+	}
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(mock.SentMessages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if sent := mock.SentMessages(); len(sent) != 1 {
+		t.Errorf("message must be delivered once; got: %v", sent)
+	}
+
+	if err := r.SendMessage("bob@example.com", "ещё раз"); err != nil {
+		t.Fatal(err)
+	}
+	if sent := mock.SentMessages(); len(sent) != 2 {
+		t.Errorf("a new call must get a new key; got: %v", sent)
+	}
+}
+
+func TestResilientLateFetch(t *testing.T) {
+	release := make(chan struct{})
+	var once sync.Once
+	mock := &MockClient{}
+	mock.FetchFunc = func() ([]Message, error) {
+		// первый запрос забирает сообщения из API, но ответ приходит слишком поздно
+		blocked := false
+		once.Do(func() { blocked = true })
+		if blocked {
+			<-release
+			return []Message{{ID: "1", Text: "привет"}}, nil
+		}
+		return nil, nil
+	}
+	r := NewResilient(mock, Options{MaxAttempts: 2, Timeout: 50 * time.Millisecond, BaseDelay: time.Millisecond, Rand: &fixedRand{}})
+
+	if _, err := r.FetchMessages(); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout; got: %v", err)
+	}
+	close(release)
+
+	msgs, err := r.FetchMessages()
+	if err != nil || len(msgs) != 1 || msgs[0].ID != "1" {
+		t.Fatalf("late result must go to the next call; got: %v, %v", msgs, err)
+	}
+	// повтор после таймаута ждал ту же попытку, а не запрашивал API снова
+	if _, err := r.FetchMessages(); err != nil || mock.FetchCalls != 2 {
+		t.Errorf("expected one fetch in flight and one new; got: %v after %d calls", err, mock.FetchCalls)
+	}
+}
+
+// plainClient — клиент без ключей идемпотентности
+type plainClient struct {
+	Client
+}
+
+func TestResilientSendWithoutKeys(t *testing.T) {
+	mock := &MockClient{SendFunc: func(string, string) error { return errTransient }}
+	r := NewResilient(plainClient{mock}, Options{Clock: &fakeClock{}, Rand: &fixedRand{}})
+	if err := r.SendMessage("bob@example.com", "привет"); err != errTransient || mock.SendCalls != 1 {
+		t.Errorf("send without keys must not be retried; got: %v after %d calls", err, mock.SendCalls)
+	}
+}
+
+func TestResilientSendTimeoutWithoutKeys(t *testing.T) {
+	release := make(chan struct{})
+	mock := &MockClient{SendFunc: func(string, string) error {
+		<-release
+		return nil
+	}}
+	r := NewResilient(plainClient{mock}, Options{Timeout: 10 * time.Millisecond, Rand: &fixedRand{}})
+	if err := r.SendMessage("bob@example.com", "привет"); !errors.Is(err, ErrOutcomeUnknown) || errors.Is(err, ErrTimeout) {
+		t.Errorf("expected outcome unknown; got: %v", err)
+	}
+	close(release)
+
+	// зависшая отправка дошла, и повтора, который создал бы дубликат, не было
+	deadline := time.Now().Add(5 * time.Second)
+	for len(mock.SentMessages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	mock.mu.Lock()
+	calls := mock.SendCalls
+	mock.mu.Unlock()
+	if sent := mock.SentMessages(); len(sent) != 1 || calls != 1 {
+		t.Errorf("send without keys must be tried once; got: %v after %d calls", sent, calls)
+	}
+}
+
+func TestBigAPIClientIdempotency(t *testing.T) {
+	api := &BigAPIClient{}
+	if err := api.SendMessageWithKey("k", "bob@example.com", "привет"); err != ErrNotConnected {
+		t.Errorf("expected %v; got: %v", ErrNotConnected, err)
+	}
+	api.Connect()
+	r := NewResilient(api, Options{Clock: &fakeClock{}, Rand: &fixedRand{}})
+	if err := r.SendMessage("bob@example.com", "привет"); err != nil {
+		t.Fatal(err)
+	}
+	api.SendMessageWithKey("k", "bob@example.com", "пока")
+	api.SendMessageWithKey("k", "bob@example.com", "пока")
+	if msgs, _ := api.FetchMessages(); len(msgs) != 2 || msgs[1].Text != "пока" {
+		t.Errorf("repeated key must not create a second message; got: %v", msgs)
+	}
+}
+
+func TestMyFunc(t *testing.T) {
+	mock := &MockClient{}
+	msgs, err := MyFunc(NewResilient(mock, Options{}))
+	if err != nil || len(msgs) != 2 || msgs[0].Text != "привет" {
+		t.Errorf("unexpected messages: %v, %v", msgs, err)
+	}
+
+	api := &BigAPIClient{}
+	if _, err := MyFunc(api); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected; got: %v", err)
+	}
+	api.Connect()
+	api.SendMessage("bob@example.com", "привет")
+	if msgs, err := MyFunc(api); err != nil || len(msgs) != 1 || msgs[0].ID != "1" {
+		t.Errorf("unexpected messages: %v, %v", msgs, err)
+	}
+}
@@ -1,26 +1,203 @@
 package company
 
-// Worker — интерфейс работника компании
-type Worker interface {
-	// всё, что он должен уметь делать, — это работать
-	Work(tasks []string) string
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrUnknownWorker — сотрудника с таким номером никогда не нанимали
+	ErrUnknownWorker = errors.New("company: unknown worker")
+	// ErrWorkerFired — сотрудник с таким номером уже уволен
+	ErrWorkerFired = errors.New("company: worker fired")
+	// ErrNotRepairable — сотрудника нельзя отремонтировать
+	ErrNotRepairable = errors.New("company: worker is not repairable")
+)
+
+// WorkerError — ошибка операции с конкретным сотрудником.
+// Err — одна из ошибок ErrUnknownWorker, ErrWorkerFired или ErrNotRepairable, её можно проверить через errors.Is
+type WorkerError struct {
+	ID  int
+	Err error
+}
+
+func (e *WorkerError) Error() string {
+	return fmt.Sprintf("%v: id %d", e.Err, e.ID)
+}
+
+func (e *WorkerError) Unwrap() error {
+	return e.Err
+}
+
+// Employee — сотрудник вместе с его постоянным номером в компании
+type Employee struct {
+	ID     int
+	Worker Worker
+}
+
+// employee — запись о сотруднике внутри компании
+type employee struct {
+	id     int
+	worker Worker
+	// busy — не даёт одному сотруднику выполнять несколько поручений одновременно
+	busy sync.Mutex
 }
 
-// Company — структура компании
+// Company — структура компании. Все методы можно вызывать из нескольких горутин
 type Company struct {
-	// personal — сотрудники компании
-	// обратите внимание, мы создали слайс сотрудников компании, то есть слайс переменных интерфейсного типа Worker
-	personal []Worker
+	mu sync.RWMutex
+	// personal — сотрудники компании в порядке найма
+	// обратите внимание, мы создали слайс сотрудников компании, то есть слайс записей с переменными интерфейсного типа Worker
+	personal []*employee
+	// byID — те же сотрудники, но по номеру
+	byID map[int]*employee
+	// fired — номера уволенных сотрудников; номера не переиспользуются
+	fired  map[int]bool
+	nextID int
+	// timesheet — учтённое время работы, см. Payroll
+	timesheet []TimeEntry
 }
 
-// Hire — наём нового сотрудника
+// Hire — наём нового сотрудника. Возвращает его постоянный номер
 // Сотрудник может быть любого типа: человек, робот или сторожевая собака. Главное, чтобы он умел работать, то есть удовлетворял интерфейсу Worker
 // Go ещё на этапе компиляции проверяет, соответствует ли интерфейсу переданная переменная
-func (с *Company) Hire(newbie Worker) {
-	с.personal = append(с.personal, newbie)
+func (c *Company) Hire(newbie Worker) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byID == nil {
+		c.byID = make(map[int]*employee)
+	}
+	e := &employee{id: c.nextID, worker: newbie}
+	c.nextID++
+	c.personal = append(c.personal, e)
+	c.byID[e.id] = e
+	return e.id
+}
+
+// Fire — увольнение сотрудника. Его номер больше никому не достанется
+func (c *Company) Fire(id int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.find(id); err != nil {
+		return err
+	}
+	delete(c.byID, id)
+	for i, e := range c.personal {
+		if e.id == id {
+			c.personal = append(c.personal[:i], c.personal[i+1:]...)
+			break
+		}
+	}
+	if c.fired == nil {
+		c.fired = make(map[int]bool)
+	}
+	c.fired[id] = true
+	return nil
+}
+
+// Lookup — поиск сотрудника по номеру
+func (c *Company) Lookup(id int) (Worker, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, err := c.find(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.worker, nil
+}
+
+// List — все работающие сотрудники в порядке найма
+func (c *Company) List() []Employee {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]Employee, len(c.personal))
+	for i, e := range c.personal {
+		res[i] = Employee{ID: e.id, Worker: e.worker}
+	}
+	return res
+}
+
+// Replace — ставит на место сотрудника id другого работника, сохраняя номер
+func (c *Company) Replace(id int, w Worker) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, err := c.find(id)
+	if err != nil {
+		return err
+	}
+	e.worker = w
+	return nil
+}
+
+// Repair — ремонт сотрудника, который реализует Repairable.
+// Ремонт начинается, когда сотрудник закончит текущую задачу
+func (c *Company) Repair(id int) error {
+	c.mu.RLock()
+	e, err := c.find(id)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	e.busy.Lock()
+	defer e.busy.Unlock()
+	c.mu.RLock()
+	w := e.worker
+	c.mu.RUnlock()
+	r, ok := underlying(w).(Repairable)
+	if !ok {
+		return &WorkerError{ID: id, Err: ErrNotRepairable}
+	}
+	r.Repair()
+	return nil
+}
+
+// Process — работа конкретного сотрудника: задачи выполняются по порядку, по результату на каждую.
+// Время работы над каждой задачей попадает в табель и оплачивается, см. Payroll
+func (c *Company) Process(id int, tasks []string) ([]TaskResult, error) {
+	c.mu.RLock()
+	e, err := c.find(id)
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]TaskResult, len(tasks))
+	for i, task := range tasks {
+		res[i] = c.work(context.Background(), e, task)
+	}
+	return res, nil
+}
+
+// work — поручает задачу сотруднику, дождавшись, пока он закончит предыдущую, и учитывает затраченное время.
+// Блокировка компании на время работы не держится
+func (c *Company) work(ctx context.Context, e *employee, task string) TaskResult {
+	e.busy.Lock()
+	defer e.busy.Unlock()
+	c.mu.RLock()
+	w := e.worker
+	c.mu.RUnlock()
+	start := now()
+	res := w.Do(ctx, task)
+	c.record(e, w, res, start, now().Sub(start))
+	return res
+}
+
+// active — снимок работающих сотрудников; вызывающий не должен держать c.mu
+func (c *Company) active() []*employee {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]*employee(nil), c.personal...)
 }
 
-// Process — работа конкретного сотрудника
-func (с Company) Process(id int, tasks []string) (res string) {
-	return c.personal[id].Work(tasks)
+// find — сотрудник по номеру; вызывающий должен держать c.mu
+func (c *Company) find(id int) (*employee, error) {
+	if e, ok := c.byID[id]; ok {
+		return e, nil
+	}
+	if c.fired[id] {
+		return nil, &WorkerError{ID: id, Err: ErrWorkerFired}
+	}
+	return nil, &WorkerError{ID: id, Err: ErrUnknownWorker}
 }
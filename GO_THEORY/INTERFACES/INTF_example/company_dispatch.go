@@ -0,0 +1,245 @@
+package company
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrNoWorkers — в компании некому раздать задачи
+	ErrNoWorkers = errors.New("company: no workers hired")
+	// ErrNoAvailableWorkers — все сотрудники недоступны, например все роботы сломаны
+	ErrNoAvailableWorkers = errors.New("company: no available workers")
+)
+
+// Strategy — способ выбора сотрудника для очередной задачи
+type Strategy int
+
+const (
+	// RoundRobin — задачи раздаются сотрудникам по кругу
+	RoundRobin Strategy = iota
+	// LeastLoaded — задача достаётся сотруднику с наименьшим числом задач в очереди и в работе
+	LeastLoaded
+	// BestMatch — задача достаётся самому узкому специалисту, у которого меньше всего лишних навыков.
+	// Если он уже набрал столько задач, сколько позволяет его Capacity, задача достаётся следующему
+	// подходящему; если заняты все — наименее загруженному из подходящих
+	BestMatch
+)
+
+// DispatchOptions — настройки раздачи задач
+type DispatchOptions struct {
+	Strategy Strategy
+	// QueueSize — ёмкость очереди каждого сотрудника. Когда очереди заполнены,
+	// раздача задач ждёт, пока освободится место. По умолчанию 1
+	QueueSize int
+	// TaskTimeout — ограничение времени на одну задачу. Ноль — без ограничения
+	TaskTimeout time.Duration
+}
+
+// Result — итог выполнения одной задачи вместе с тем, кто её выполнял
+type Result struct {
+	// Index — позиция задачи в переданном слайсе
+	Index int
+	// WorkerID — постоянный номер сотрудника, выполнившего задачу; -1, если задача не была выдана
+	WorkerID int
+	// Worker — описание сотрудника, например имя человека или модель робота
+	Worker string
+	TaskResult
+}
+
+// job — задача в очереди конкретного сотрудника
+type job struct {
+	index int
+	task  string
+}
+
+// poolWorker — сотрудник вместе с его очередью, навыками и текущей нагрузкой
+type poolWorker struct {
+	emp      *employee
+	skills   map[string]bool
+	capacity int
+	queue    chan job
+	load     int64
+}
+
+// Dispatch — раздаёт задачи без требований к навыкам, см. DispatchTasks
+func (c *Company) Dispatch(ctx context.Context, tasks []string, opts DispatchOptions) (<-chan Result, error) {
+	plain := make([]Task, len(tasks))
+	for i, name := range tasks {
+		plain[i] = Task{Name: name}
+	}
+	return c.DispatchTasks(ctx, plain, opts)
+}
+
+// DispatchTasks — раздаёт задачи всем сотрудникам компании и выполняет их параллельно.
+// Задача достаётся только сотруднику, у которого есть все нужные ей навыки (см. Skilled);
+// если таких в компании нет, задача возвращается со статусом StatusFailed и ошибкой ErrNoQualifiedWorker.
+// Состав сотрудников фиксируется в момент вызова. Каждый сотрудник берёт задачи
+// из своей очереди строго по одной и не пересекается с вызовами Process.
+// Результаты приходят в канал по мере готовности; канал закрывается, когда готовы все задачи.
+// Недоступных сотрудников (см. Availability) Dispatch пропускает; если доступных нет,
+// задача возвращается со статусом StatusFailed и ошибкой ErrNoAvailableWorkers.
+// При отмене ctx задачи, которые ещё не были выданы, возвращаются со статусом StatusCanceled
+func (c *Company) DispatchTasks(ctx context.Context, tasks []Task, opts DispatchOptions) (<-chan Result, error) {
+	staff := c.active()
+	if len(staff) == 0 {
+		return nil, ErrNoWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1
+	}
+
+	workers := make([]*poolWorker, len(staff))
+	for i, e := range staff {
+		c.mu.RLock()
+		skills, capacity := profile(e.worker)
+		c.mu.RUnlock()
+		workers[i] = &poolWorker{emp: e, skills: skills, capacity: capacity, queue: make(chan job, opts.QueueSize)}
+	}
+
+	results := make(chan Result, len(tasks))
+	var wg sync.WaitGroup
+	for _, pw := range workers {
+		wg.Add(1)
+		go func(pw *poolWorker) {
+			defer wg.Done()
+			for j := range pw.queue {
+				res, pending := c.run(ctx, pw, j, opts.TaskTimeout)
+				results <- res
+				if pending != nil {
+					<-pending
+				}
+				atomic.AddInt64(&pw.load, -1)
+			}
+		}(pw)
+	}
+
+	go func() {
+		next := 0
+		for i, task := range tasks {
+			if err := ctx.Err(); err != nil {
+				results <- canceled(i, task.Name, err)
+				continue
+			}
+			pw, err := c.pick(workers, task, opts.Strategy, &next)
+			if err != nil {
+				results <- Result{Index: i, WorkerID: -1, TaskResult: TaskResult{Task: task.Name, Status: StatusFailed, Err: err}}
+				continue
+			}
+			atomic.AddInt64(&pw.load, 1)
+			select {
+			case pw.queue <- job{index: i, task: task.Name}:
+			case <-ctx.Done():
+				atomic.AddInt64(&pw.load, -1)
+				results <- canceled(i, task.Name, ctx.Err())
+			}
+		}
+		for _, pw := range workers {
+			close(pw.queue)
+		}
+		wg.Wait()
+		close(results)
+	}()
+	return results, nil
+}
+
+// run — выполняет одну задачу с учётом таймаута.
+// Если время вышло, результат с ошибкой возвращается сразу вместе с каналом pending:
+// следующую задачу сотрудник должен начать только после того, как из него придёт значение
+func (c *Company) run(ctx context.Context, pw *poolWorker, j job, timeout time.Duration) (res Result, pending <-chan TaskResult) {
+	c.mu.RLock()
+	res = Result{Index: j.index, WorkerID: pw.emp.id, Worker: fmt.Sprint(pw.emp.worker)}
+	c.mu.RUnlock()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		res.TaskResult = TaskResult{Task: j.task, Status: StatusCanceled, Err: err}
+		return res, nil
+	}
+
+	// контекст передаётся сотруднику, но не все сотрудники его слушают,
+	// поэтому таймаут дополнительно отслеживается здесь
+	start := time.Now()
+	done := make(chan TaskResult, 1)
+	go func() {
+		done <- c.work(ctx, pw.emp, j.task)
+	}()
+	select {
+	case res.TaskResult = <-done:
+	case <-ctx.Done():
+		res.TaskResult = TaskResult{Task: j.task, Status: StatusCanceled, Err: ctx.Err(), Duration: time.Since(start)}
+		pending = done
+	}
+	return res, pending
+}
+
+// canceled — результат задачи, которую не успели выдать сотруднику
+func canceled(index int, task string, err error) Result {
+	return Result{Index: index, WorkerID: -1, TaskResult: TaskResult{Task: task, Status: StatusCanceled, Err: err}}
+}
+
+// pick — выбирает сотрудника для задачи по стратегии. Для RoundRobin next — позиция в круге,
+// pick сдвигает её за выбранного сотрудника
+func (c *Company) pick(workers []*poolWorker, task Task, strategy Strategy, next *int) (*poolWorker, error) {
+	var candidates []*poolWorker
+	qualified := false
+	for _, pw := range workers {
+		if !pw.qualified(task) {
+			continue
+		}
+		qualified = true
+		if c.available(pw.emp) {
+			candidates = append(candidates, pw)
+		}
+	}
+	switch {
+	case !qualified:
+		return nil, ErrNoQualifiedWorker
+	case len(candidates) == 0:
+		return nil, ErrNoAvailableWorkers
+	}
+
+	switch strategy {
+	case LeastLoaded:
+		return leastLoaded(candidates), nil
+	case BestMatch:
+		return bestMatch(candidates), nil
+	default:
+		for k := 0; k < len(workers); k++ {
+			pw := workers[(*next+k)%len(workers)]
+			for _, cand := range candidates {
+				if cand == pw {
+					*next += k + 1
+					return pw, nil
+				}
+			}
+		}
+		return candidates[0], nil
+	}
+}
+
+// leastLoaded — сотрудник с наименьшей нагрузкой; при равенстве — нанятый раньше
+func leastLoaded(workers []*poolWorker) *poolWorker {
+	best := workers[0]
+	for _, pw := range workers[1:] {
+		if atomic.LoadInt64(&pw.load) < atomic.LoadInt64(&best.load) {
+			best = pw
+		}
+	}
+	return best
+}
+
+// available — можно ли выдать сотруднику задачу; сломанных роботов компания обходит стороной
+func (c *Company) available(e *employee) bool {
+	c.mu.RLock()
+	w := e.worker
+	c.mu.RUnlock()
+	return available(w)
+}
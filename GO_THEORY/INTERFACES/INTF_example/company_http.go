@@ -0,0 +1,410 @@
+package company
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// openAPI — описание HTTP API в формате OpenAPI 3
+//
+//go:embed company_openapi.yaml
+var openAPI []byte
+
+// Server — HTTP API компании, JSON поверх REST:
+//
+//	GET    /workers       — список сотрудников
+//	POST   /workers       — наём сотрудника зарегистрированного типа (см. RegisterKind)
+//	DELETE /workers/{id}  — увольнение
+//	POST   /batches       — раздача пачки задач через DispatchTasks
+//	GET    /batches/{id}  — состояние пачки и готовые результаты
+//	GET    /openapi.yaml  — описание API
+//
+// Пачки выполняются в фоне и не зависят от запроса, который их создал; Close отменяет незавершённые.
+// Завершённые пачки хранятся BatchTTL, и их не больше MaxBatches: лишние, начиная со старых,
+// забываются при отправке новой пачки
+type Server struct {
+	// BatchTTL — сколько хранить результаты завершённой пачки. По умолчанию 10 мин
+	BatchTTL time.Duration
+	// MaxBatches — сколько завершённых пачек хранить. По умолчанию 1000.
+	// Настройки меняются до начала работы сервера
+	MaxBatches int
+
+	company *Company
+	ctx     context.Context
+	cancel  context.CancelFunc
+	// wg — горутины, которые собирают результаты пачек
+	wg sync.WaitGroup
+
+	mu      sync.Mutex
+	batches map[int]*batch
+	nextID  int
+	closed  bool
+}
+
+// NewServer — создаёт HTTP API для компании c
+func NewServer(c *Company) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		BatchTTL:   10 * time.Minute,
+		MaxBatches: 1000,
+		company:    c,
+		ctx:        ctx,
+		cancel:     cancel,
+		batches:    make(map[int]*batch),
+	}
+}
+
+// Close — отменяет задачи, которые ещё выполняются, и ждёт, пока соберутся их результаты.
+// Новые пачки после Close не принимаются
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cancel()
+	s.wg.Wait()
+}
+
+// ServeHTTP — разбирает путь и передаёт запрос обработчику
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	collection, id, hasID := splitPath(r.URL.Path)
+	switch {
+	case collection == "workers" && !hasID:
+		s.route(w, r, map[string]http.HandlerFunc{http.MethodGet: s.listWorkers, http.MethodPost: s.hireWorker})
+	case collection == "workers":
+		s.route(w, r, map[string]http.HandlerFunc{http.MethodDelete: func(w http.ResponseWriter, r *http.Request) { s.fireWorker(w, id) }})
+	case collection == "batches" && !hasID:
+		s.route(w, r, map[string]http.HandlerFunc{http.MethodPost: s.submitBatch})
+	case collection == "batches":
+		s.route(w, r, map[string]http.HandlerFunc{http.MethodGet: func(w http.ResponseWriter, r *http.Request) { s.getBatch(w, id) }})
+	case collection == "openapi.yaml" && !hasID:
+		s.route(w, r, map[string]http.HandlerFunc{http.MethodGet: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/yaml")
+			w.Write(openAPI)
+		}})
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// splitPath — «/workers/7» превращает в ("workers", "7", true)
+func splitPath(path string) (collection, id string, hasID bool) {
+	path = strings.Trim(path, "/")
+	collection, id, hasID = strings.Cut(path, "/")
+	return collection, id, hasID
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, methods map[string]http.HandlerFunc) {
+	h, ok := methods[r.Method]
+	if !ok {
+		allowed := make([]string, 0, len(methods))
+		for m := range methods {
+			allowed = append(allowed, m)
+		}
+		sort.Strings(allowed)
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	h(w, r)
+}
+
+// workerJSON — сотрудник в ответах API
+type workerJSON struct {
+	ID          int    `json:"id"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+}
+
+func newWorkerJSON(e Employee) workerJSON {
+	return workerJSON{ID: e.ID, Kind: kindName(e.Worker), Description: fmt.Sprint(e.Worker)}
+}
+
+func (s *Server) listWorkers(w http.ResponseWriter, r *http.Request) {
+	res := []workerJSON{}
+	for _, e := range s.company.List() {
+		res = append(res, newWorkerJSON(e))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// hireRequest — тело POST /workers: зарегистрированный тип и состояние сотрудника в том же виде, что в Export
+type hireRequest struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (s *Server) hireWorker(w http.ResponseWriter, r *http.Request) {
+	var req hireRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Kind == "" {
+		writeError(w, http.StatusUnprocessableEntity, "kind is required")
+		return
+	}
+	if len(req.Data) == 0 {
+		req.Data = json.RawMessage("{}")
+	}
+	worker, err := decodeWorker(rosterEntry{ID: -1, Kind: req.Kind, Data: req.Data})
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	id := s.company.Hire(worker)
+	w.Header().Set("Location", "/workers/"+strconv.Itoa(id))
+	writeJSON(w, http.StatusCreated, newWorkerJSON(Employee{ID: id, Worker: worker}))
+}
+
+func (s *Server) fireWorker(w http.ResponseWriter, rawID string) {
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid worker id %q", rawID))
+		return
+	}
+	switch err := s.company.Fire(id); {
+	case errors.Is(err, ErrWorkerFired):
+		writeError(w, http.StatusGone, err.Error())
+	case err != nil:
+		writeError(w, http.StatusNotFound, err.Error())
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// batchRequest — тело POST /batches
+type batchRequest struct {
+	Tasks []struct {
+		Name     string   `json:"name"`
+		Requires []string `json:"requires,omitempty"`
+	} `json:"tasks"`
+	// Strategy — round_robin (по умолчанию), least_loaded или best_match
+	Strategy string `json:"strategy,omitempty"`
+	// Timeout — ограничение на одну задачу в формате time.ParseDuration, например "2s"
+	Timeout   string `json:"timeout,omitempty"`
+	QueueSize int    `json:"queue_size,omitempty"`
+}
+
+var strategies = map[string]Strategy{
+	"":             RoundRobin,
+	"round_robin":  RoundRobin,
+	"least_loaded": LeastLoaded,
+	"best_match":   BestMatch,
+}
+
+// options — проверяет запрос и превращает его в аргументы DispatchTasks
+func (req batchRequest) options() ([]Task, DispatchOptions, error) {
+	var opts DispatchOptions
+	if len(req.Tasks) == 0 {
+		return nil, opts, errors.New("tasks must not be empty")
+	}
+	tasks := make([]Task, len(req.Tasks))
+	for i, t := range req.Tasks {
+		if strings.TrimSpace(t.Name) == "" {
+			return nil, opts, fmt.Errorf("tasks[%d].name is required", i)
+		}
+		tasks[i] = Task{Name: t.Name, Requires: t.Requires}
+	}
+	strategy, ok := strategies[req.Strategy]
+	if !ok {
+		return nil, opts, fmt.Errorf("unknown strategy %q", req.Strategy)
+	}
+	opts.Strategy = strategy
+	if req.QueueSize < 0 {
+		return nil, opts, errors.New("queue_size must not be negative")
+	}
+	opts.QueueSize = req.QueueSize
+	if req.Timeout != "" {
+		d, err := time.ParseDuration(req.Timeout)
+		if err != nil || d < 0 {
+			return nil, opts, fmt.Errorf("invalid timeout %q", req.Timeout)
+		}
+		opts.TaskTimeout = d
+	}
+	return tasks, opts, nil
+}
+
+// batch — пачка задач, отправленная через API
+type batch struct {
+	id    int
+	total int
+
+	mu      sync.Mutex
+	results []resultJSON
+	done    bool
+	// finished — когда пачка завершилась
+	finished time.Time
+}
+
+// resultJSON — результат задачи в ответах API
+type resultJSON struct {
+	Index      int    `json:"index"`
+	WorkerID   int    `json:"worker_id"`
+	Worker     string `json:"worker,omitempty"`
+	Task       string `json:"task"`
+	Status     string `json:"status"`
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// batchJSON — состояние пачки в ответах API
+type batchJSON struct {
+	ID      int          `json:"id"`
+	State   string       `json:"state"`
+	Total   int          `json:"total"`
+	Results []resultJSON `json:"results"`
+}
+
+func (b *batch) snapshot() batchJSON {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	res := batchJSON{ID: b.id, State: "running", Total: b.total, Results: append([]resultJSON{}, b.results...)}
+	if b.done {
+		res.State = "done"
+	}
+	return res
+}
+
+func (s *Server) submitBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	tasks, opts, err := req.options()
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	// под s.mu только резервируем номер пачки: раздача задач может занять время,
+	// и держать на ней сервер нельзя. wg.Add тоже под s.mu, чтобы Close дождался пачки
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, "server is shutting down")
+		return
+	}
+	s.evictLocked()
+	b := &batch{id: s.nextID, total: len(tasks)}
+	s.nextID++
+	s.batches[b.id] = b
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	ch, err := s.company.DispatchTasks(s.ctx, tasks, opts)
+	if err != nil {
+		s.mu.Lock()
+		delete(s.batches, b.id)
+		s.mu.Unlock()
+		s.wg.Done()
+		if errors.Is(err, ErrNoWorkers) {
+			writeError(w, http.StatusConflict, err.Error())
+		} else {
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	go func() {
+		defer s.wg.Done()
+		for res := range ch {
+			r := resultJSON{
+				Index:      res.Index,
+				WorkerID:   res.WorkerID,
+				Worker:     res.Worker,
+				Task:       res.Task,
+				Status:     res.Status.String(),
+				Output:     res.Output,
+				DurationMS: res.Duration.Milliseconds(),
+			}
+			if res.Err != nil {
+				r.Error = res.Err.Error()
+			}
+			b.mu.Lock()
+			b.results = append(b.results, r)
+			b.mu.Unlock()
+		}
+		b.mu.Lock()
+		b.done, b.finished = true, now()
+		b.mu.Unlock()
+	}()
+
+	w.Header().Set("Location", "/batches/"+strconv.Itoa(b.id))
+	writeJSON(w, http.StatusAccepted, b.snapshot())
+}
+
+// evictLocked — забывает завершённые пачки старше BatchTTL, а затем самые старые из завершённых,
+// пока их не останется MaxBatches. Идущие пачки не трогает; вызывающий должен держать s.mu
+func (s *Server) evictLocked() {
+	deadline := now().Add(-s.BatchTTL)
+	var finished []int
+	for id, b := range s.batches {
+		b.mu.Lock()
+		done, at := b.done, b.finished
+		b.mu.Unlock()
+		if !done {
+			continue
+		}
+		if !at.After(deadline) {
+			delete(s.batches, id)
+			continue
+		}
+		finished = append(finished, id)
+	}
+	if extra := len(finished) - s.MaxBatches; extra > 0 {
+		sort.Ints(finished)
+		for _, id := range finished[:extra] {
+			delete(s.batches, id)
+		}
+	}
+}
+
+func (s *Server) getBatch(w http.ResponseWriter, rawID string) {
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid batch id %q", rawID))
+		return
+	}
+	s.mu.Lock()
+	b, ok := s.batches[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("company: unknown batch: id %d", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, b.snapshot())
+}
+
+// decodeBody — читает JSON из тела запроса; при ошибке сам отвечает 400 или 415
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+		writeError(w, http.StatusUnsupportedMediaType, "content type must be application/json")
+		return false
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
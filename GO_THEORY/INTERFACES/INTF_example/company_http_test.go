@@ -0,0 +1,237 @@
+package company
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// call — выполняет запрос к API и декодирует JSON-ответ в out, если он не nil
+func call(t *testing.T, h http.Handler, method, path, body string, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+func TestServerWorkers(t *testing.T) {
+	s := NewServer(&Company{})
+	defer s.Close()
+
+	var hired workerJSON
+	rec := call(t, s, http.MethodPost, "/workers", `{"kind": "stored", "data": {"name": "alice"}}`, &hired)
+	if rec.Code != http.StatusCreated || hired.Kind != "stored" || rec.Header().Get("Location") != "/workers/0" {
+		t.Fatalf("unexpected hire response %d: %s", rec.Code, rec.Body)
+	}
+	call(t, s, http.MethodPost, "/workers", `{"kind": "stored-legacy", "data": {"model": "T-800"}}`, nil)
+
+	var list []workerJSON
+	call(t, s, http.MethodGet, "/workers", "", &list)
+	if len(list) != 2 || list[1].Kind != "stored-legacy" || list[1].Description != "{T-800 0}" {
+		t.Errorf("unexpected roster: %+v", list)
+	}
+
+	if rec := call(t, s, http.MethodDelete, "/workers/0", "", nil); rec.Code != http.StatusNoContent {
+		t.Errorf("expected %d; got: %d", http.StatusNoContent, rec.Code)
+	}
+	call(t, s, http.MethodGet, "/workers", "", &list)
+	if len(list) != 1 || list[0].ID != 1 {
+		t.Errorf("fired worker must leave the roster: %+v", list)
+	}
+}
+
+func TestServerValidation(t *testing.T) {
+	s := NewServer(&Company{})
+	defer s.Close()
+
+	tests := []struct {
+		method, path, body string
+		code               int
+	}{
+		{http.MethodPost, "/workers", `{"kind": `, http.StatusBadRequest},
+		{http.MethodPost, "/workers", `{"kind": "stored", "salary": 1}`, http.StatusBadRequest},
+		{http.MethodPost, "/workers", `{}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/workers", `{"kind": "dragon"}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/workers", `{"kind": "stored", "data": {"name": 1}}`, http.StatusUnprocessableEntity},
+		{http.MethodDelete, "/workers/abc", "", http.StatusBadRequest},
+		{http.MethodDelete, "/workers/42", "", http.StatusNotFound},
+		{http.MethodPost, "/batches", `{"tasks": [{"name": "a"}]}`, http.StatusConflict},
+		{http.MethodPost, "/batches", `{"tasks": []}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/batches", `{"tasks": [{"name": " "}]}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/batches", `{"tasks": [{"name": "a"}], "strategy": "random"}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/batches", `{"tasks": [{"name": "a"}], "timeout": "soon"}`, http.StatusUnprocessableEntity},
+		{http.MethodGet, "/batches/7", "", http.StatusNotFound},
+		{http.MethodGet, "/batches/x", "", http.StatusBadRequest},
+		{http.MethodPut, "/workers", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/payroll", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		var body map[string]string
+		rec := call(t, s, tt.method, tt.path, tt.body, &body)
+		if rec.Code != tt.code {
+			t.Errorf("%s %s %s: expected %d; got: %d %s", tt.method, tt.path, tt.body, tt.code, rec.Code, rec.Body)
+		}
+		if body["error"] == "" {
+			t.Errorf("%s %s: error message is missing", tt.method, tt.path)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/workers", strings.NewReader(`kind=stored`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected %d; got: %d", http.StatusUnsupportedMediaType, rec.Code)
+	}
+
+	call(t, s, http.MethodPost, "/workers", `{"kind": "stored"}`, nil)
+	call(t, s, http.MethodDelete, "/workers/0", "", nil)
+	if rec := call(t, s, http.MethodDelete, "/workers/0", "", nil); rec.Code != http.StatusGone {
+		t.Errorf("expected %d for a fired worker; got: %d", http.StatusGone, rec.Code)
+	}
+}
+
+func TestServerBatches(t *testing.T) {
+	s := NewServer(&Company{})
+	defer s.Close()
+	call(t, s, http.MethodPost, "/workers", `{"kind": "stored", "data": {"name": "alice"}}`, nil)
+
+	var b batchJSON
+	rec := call(t, s, http.MethodPost, "/batches", `{"tasks": [{"name": "a"}, {"name": "b"}, {"name": "fly", "requires": ["pilot"]}], "strategy": "best_match"}`, &b)
+	if rec.Code != http.StatusAccepted || b.Total != 3 {
+		t.Fatalf("unexpected submit response %d: %s", rec.Code, rec.Body)
+	}
+	location := rec.Header().Get("Location")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for b.State != "done" {
+		if time.Now().After(deadline) {
+			t.Fatalf("batch is not done: %+v", b)
+		}
+		time.Sleep(time.Millisecond)
+		call(t, s, http.MethodGet, location, "", &b)
+	}
+	if len(b.Results) != 3 {
+		t.Fatalf("expected 3 results; got: %+v", b.Results)
+	}
+	for _, r := range b.Results {
+		switch {
+		case r.Task == "fly" && (r.Status != "failed" || r.WorkerID != -1 || r.Error == ""):
+			t.Errorf("task nobody can do must fail: %+v", r)
+		case r.Task != "fly" && (r.Status != "done" || r.Output != "alice"):
+			t.Errorf("unexpected result: %+v", r)
+		}
+	}
+}
+
+func TestServerOpenAPI(t *testing.T) {
+	s := NewServer(&Company{})
+	defer s.Close()
+	rec := call(t, s, http.MethodGet, "/openapi.yaml", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d; got: %d", http.StatusOK, rec.Code)
+	}
+	var doc struct {
+		OpenAPI string                            `yaml:"openapi"`
+		Paths   map[string]map[string]interface{} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for path, methods := range map[string][]string{
+		"/workers":      {"get", "post"},
+		"/workers/{id}": {"delete"},
+		"/batches":      {"post"},
+		"/batches/{id}": {"get"},
+		"/openapi.yaml": {"get"},
+	} {
+		for _, m := range methods {
+			op, ok := doc.Paths[path][m]
+			if !ok {
+				t.Errorf("%s %s is not documented", m, path)
+				continue
+			}
+			responses, _ := op.(map[string]interface{})["responses"].(map[string]interface{})
+			if _, ok := responses["405"]; !ok {
+				t.Errorf("%s %s: 405 response is not documented", m, path)
+			}
+		}
+	}
+}
+
+// waitBatch — ждёт, пока пачка по адресу location завершится
+func waitBatch(t *testing.T, s *Server, location string) {
+	t.Helper()
+	var b batchJSON
+	deadline := time.Now().Add(5 * time.Second)
+	for b.State != "done" {
+		if time.Now().After(deadline) {
+			t.Fatalf("batch is not done: %+v", b)
+		}
+		time.Sleep(time.Millisecond)
+		call(t, s, http.MethodGet, location, "", &b)
+	}
+}
+
+func TestServerBatchEviction(t *testing.T) {
+	s := NewServer(&Company{})
+	defer s.Close()
+	s.MaxBatches = 1
+	call(t, s, http.MethodPost, "/workers", `{"kind": "stored", "data": {"name": "alice"}}`, nil)
+
+	submit := func() string {
+		rec := call(t, s, http.MethodPost, "/batches", `{"tasks": [{"name": "a"}]}`, nil)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("unexpected submit response %d: %s", rec.Code, rec.Body)
+		}
+		return rec.Header().Get("Location")
+	}
+	first := submit()
+	waitBatch(t, s, first)
+	second := submit()
+	waitBatch(t, s, second)
+	third := submit()
+	if rec := call(t, s, http.MethodGet, first, "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("oldest finished batch must be evicted over the limit; got: %d", rec.Code)
+	}
+	if rec := call(t, s, http.MethodGet, second, "", nil); rec.Code != http.StatusOK {
+		t.Errorf("newest finished batch must be kept; got: %d", rec.Code)
+	}
+
+	s.BatchTTL = time.Nanosecond
+	waitBatch(t, s, third)
+	submit()
+	if rec := call(t, s, http.MethodGet, third, "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("expired batch must be evicted; got: %d", rec.Code)
+	}
+}
+
+func TestServerCloseWaitsForBatches(t *testing.T) {
+	s := NewServer(&Company{})
+	call(t, s, http.MethodPost, "/workers", `{"kind": "stored", "data": {"name": "alice"}}`, nil)
+	call(t, s, http.MethodPost, "/batches", `{"tasks": [{"name": "a"}, {"name": "b"}, {"name": "c"}]}`, nil)
+
+	s.Close()
+	for _, b := range s.batches {
+		if snap := b.snapshot(); snap.State != "done" || len(snap.Results) != snap.Total {
+			t.Errorf("Close must wait for results to be collected: %+v", snap)
+		}
+	}
+	if rec := call(t, s, http.MethodPost, "/batches", `{"tasks": [{"name": "a"}]}`, nil); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d after Close; got: %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
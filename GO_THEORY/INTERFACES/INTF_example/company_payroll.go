@@ -0,0 +1,215 @@
+package company
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// now — текущее время; в тестах подменяется, чтобы учёт времени был предсказуемым
+var now = time.Now
+
+// HourlyPaid — сотрудник с почасовой оплатой, например person.Person
+type HourlyPaid interface {
+	HourlyRate() float64
+}
+
+// TaskPaid — сотрудник со сдельной оплатой и износом, например *robot.Robot.
+// TaskRate начисляется за каждую выполненную задачу, DepreciationRate — за час работы
+type TaskPaid interface {
+	TaskRate() float64
+	DepreciationRate() float64
+}
+
+// TimeEntry — учтённое время работы над одной задачей и его стоимость.
+// Ставки берутся в момент выполнения, поэтому запись не меняется, если сотрудника потом уволят или заменят
+type TimeEntry struct {
+	WorkerID int
+	Worker   string
+	// Kind — имя, под которым тип сотрудника зарегистрирован через RegisterKind, или имя Go-типа
+	Kind     string
+	Task     string
+	Status   Status
+	Start    time.Time
+	Duration time.Duration
+	// Labor — оплата работы: время по почасовой ставке плюс сдельная плата, если задача выполнена
+	Labor float64
+	// Depreciation — износ за время работы
+	Depreciation float64
+}
+
+// Cost — полная стоимость записи
+func (e TimeEntry) Cost() float64 {
+	return e.Labor + e.Depreciation
+}
+
+// record — учитывает время, которое сотрудник потратил на задачу
+func (c *Company) record(e *employee, w Worker, res TaskResult, start time.Time, d time.Duration) {
+	entry := TimeEntry{
+		WorkerID: e.id,
+		Worker:   fmt.Sprint(w),
+		Kind:     kindName(w),
+		Task:     res.Task,
+		Status:   res.Status,
+		Start:    start,
+		Duration: d,
+	}
+	hours := d.Hours()
+	value := underlying(w)
+	if p, ok := value.(HourlyPaid); ok {
+		entry.Labor += p.HourlyRate() * hours
+	}
+	if p, ok := value.(TaskPaid); ok {
+		if res.Status == StatusDone {
+			entry.Labor += p.TaskRate()
+		}
+		entry.Depreciation += p.DepreciationRate() * hours
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timesheet = append(c.timesheet, entry)
+}
+
+// kindName — имя типа сотрудника для отчётов
+func kindName(w Worker) string {
+	value := underlying(w)
+	if k, ok := kindOf(value); ok {
+		return k.name
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// Timesheet — учтённое время работы, начатой в промежутке [from, to), в порядке завершения задач.
+// Нулевой to означает «без верхней границы»
+func (c *Company) Timesheet(from, to time.Time) []TimeEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var res []TimeEntry
+	for _, e := range c.timesheet {
+		if e.Start.Before(from) || !to.IsZero() && !e.Start.Before(to) {
+			continue
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+// PayLine — итог за период по одному сотруднику или по одному типу сотрудников
+type PayLine struct {
+	// WorkerID — номер сотрудника; -1 в итогах по типу
+	WorkerID int
+	Worker   string
+	Kind     string
+	// Tasks — сколько задач сотрудник брал в работу, Done — сколько из них выполнил
+	Tasks        int
+	Done         int
+	Hours        time.Duration
+	Labor        float64
+	Depreciation float64
+}
+
+// Total — полная стоимость строки
+func (l PayLine) Total() float64 {
+	return l.Labor + l.Depreciation
+}
+
+func (l *PayLine) add(e TimeEntry) {
+	l.Tasks++
+	if e.Status == StatusDone {
+		l.Done++
+	}
+	l.Hours += e.Duration
+	l.Labor += e.Labor
+	l.Depreciation += e.Depreciation
+}
+
+// Payroll — ведомость за период
+type Payroll struct {
+	From, To time.Time
+	// Workers — строки по сотрудникам в порядке номеров
+	Workers []PayLine
+	// Kinds — итоги по типам сотрудников в алфавитном порядке
+	Kinds []PayLine
+	// Total — итог по всей компании
+	Total PayLine
+}
+
+// Payroll — ведомость за период [from, to): оплата и износ по каждому сотруднику,
+// по каждому типу сотрудников и по компании в целом. Уволенные сотрудники попадают
+// в ведомость за то время, что успели отработать
+func (c *Company) Payroll(from, to time.Time) Payroll {
+	p := Payroll{From: from, To: to, Total: PayLine{WorkerID: -1}}
+	workers := make(map[int]*PayLine)
+	kinds := make(map[string]*PayLine)
+	for _, e := range c.Timesheet(from, to) {
+		w, ok := workers[e.WorkerID]
+		if !ok {
+			w = &PayLine{WorkerID: e.WorkerID, Worker: e.Worker, Kind: e.Kind}
+			workers[e.WorkerID] = w
+		}
+		k, ok := kinds[e.Kind]
+		if !ok {
+			k = &PayLine{WorkerID: -1, Kind: e.Kind}
+			kinds[e.Kind] = k
+		}
+		w.add(e)
+		k.add(e)
+		p.Total.add(e)
+	}
+
+	for _, w := range workers {
+		p.Workers = append(p.Workers, *w)
+	}
+	sort.Slice(p.Workers, func(i, j int) bool { return p.Workers[i].WorkerID < p.Workers[j].WorkerID })
+	for _, k := range kinds {
+		p.Kinds = append(p.Kinds, *k)
+	}
+	sort.Slice(p.Kinds, func(i, j int) bool { return p.Kinds[i].Kind < p.Kinds[j].Kind })
+	return p
+}
+
+// WriteCSV — выгружает ведомость в CSV. Первая колонка — раздел:
+// worker для строк по сотрудникам, kind для итогов по типам, total для итога по компании.
+// Часы записываются десятичной дробью, деньги — с точностью до сотых
+func (p Payroll) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"section", "worker_id", "worker", "kind", "tasks", "done", "hours", "labor", "depreciation", "total"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	write := func(section string, l PayLine) error {
+		id := ""
+		if l.WorkerID >= 0 {
+			id = strconv.Itoa(l.WorkerID)
+		}
+		return cw.Write([]string{
+			section, id, l.Worker, l.Kind,
+			strconv.Itoa(l.Tasks), strconv.Itoa(l.Done),
+			strconv.FormatFloat(l.Hours.Hours(), 'f', 4, 64),
+			money(l.Labor), money(l.Depreciation), money(l.Total()),
+		})
+	}
+	for _, l := range p.Workers {
+		if err := write("worker", l); err != nil {
+			return err
+		}
+	}
+	for _, l := range p.Kinds {
+		if err := write("kind", l); err != nil {
+			return err
+		}
+	}
+	if err := write("total", p.Total); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func money(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
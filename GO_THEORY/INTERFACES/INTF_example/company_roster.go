@@ -0,0 +1,254 @@
+package company
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownKind — тип сотрудника не зарегистрирован через RegisterKind
+var ErrUnknownKind = errors.New("company: unknown worker kind")
+
+// Format — формат файла со списком сотрудников
+type Format int
+
+const (
+	FormatJSON Format = iota
+	FormatYAML
+)
+
+// kind — зарегистрированный тип сотрудника
+type kind struct {
+	name string
+	typ  reflect.Type
+	// legacy — тип реализует только LegacyWorker, при загрузке его нужно обернуть в Adapt
+	legacy bool
+}
+
+var registry = struct {
+	sync.RWMutex
+	byName map[string]kind
+	byType map[reflect.Type]kind
+}{
+	byName: make(map[string]kind),
+	byType: make(map[reflect.Type]kind),
+}
+
+// RegisterKind — регистрирует тип сотрудника под именем name, чтобы его можно было сохранять и загружать.
+// proto — пример значения этого типа: Worker или LegacyWorker, например person.Person{} или &robot.Robot{}.
+// Состояние сотрудника кодируется через encoding/json, поэтому тип должен уметь
+// превращаться в JSON и обратно. Тип и указатель на него считаются одним типом:
+// после RegisterKind("person", person.Person{}) сохраняется и сотрудник Adapt(person.New(...)),
+// а загружается он в том виде, в котором тип зарегистрирован. Сам пакет company о конкретных типах ничего не знает:
+// регистрация делается там, где эти типы известны, обычно в main.
+// Повторная регистрация имени или типа — ошибка программиста, поэтому RegisterKind паникует
+func RegisterKind(name string, proto interface{}) {
+	k := kind{name: name, typ: reflect.TypeOf(proto)}
+	switch proto.(type) {
+	case Worker:
+	case LegacyWorker:
+		k.legacy = true
+	default:
+		panic(fmt.Sprintf("company: kind %q: %T is not a worker", name, proto))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.byName[name]; ok {
+		panic(fmt.Sprintf("company: kind %q registered twice", name))
+	}
+	if _, ok := registry.byType[baseType(k.typ)]; ok {
+		panic(fmt.Sprintf("company: type %v registered twice", k.typ))
+	}
+	registry.byName[name] = k
+	registry.byType[baseType(k.typ)] = k
+}
+
+// kindOf — зарегистрированный тип сотрудника value, будь value значением или указателем
+func kindOf(value interface{}) (kind, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	k, ok := registry.byType[baseType(reflect.TypeOf(value))]
+	return k, ok
+}
+
+// baseType — тип без указателя: по нему ищутся зарегистрированные типы
+func baseType(t reflect.Type) reflect.Type {
+	if t != nil && t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// rosterFile — сохранённое состояние компании
+type rosterFile struct {
+	NextID  int           `json:"next_id" yaml:"next_id"`
+	Fired   []int         `json:"fired,omitempty" yaml:"fired,omitempty"`
+	Workers []rosterEntry `json:"workers" yaml:"workers"`
+}
+
+// rosterEntry — один сотрудник: номер, зарегистрированное имя типа и его состояние
+type rosterEntry struct {
+	ID   int         `json:"id" yaml:"id"`
+	Kind string      `json:"kind" yaml:"kind"`
+	Data interface{} `json:"data" yaml:"data"`
+}
+
+// Export — сохраняет список сотрудников вместе с их номерами в выбранном формате.
+// Во время сохранения сотрудник не получает новых задач, поэтому его состояние согласовано
+func (c *Company) Export(w io.Writer, format Format) error {
+	c.mu.RLock()
+	file := rosterFile{NextID: c.nextID}
+	for id := range c.fired {
+		file.Fired = append(file.Fired, id)
+	}
+	staff := append([]*employee(nil), c.personal...)
+	c.mu.RUnlock()
+	sort.Ints(file.Fired)
+
+	for _, e := range staff {
+		entry, err := c.encodeEmployee(e, format)
+		if err != nil {
+			return err
+		}
+		file.Workers = append(file.Workers, entry)
+	}
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(file)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(file); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("company: unsupported format %d", format)
+	}
+}
+
+func (c *Company) encodeEmployee(e *employee, format Format) (rosterEntry, error) {
+	e.busy.Lock()
+	defer e.busy.Unlock()
+	c.mu.RLock()
+	w := e.worker
+	c.mu.RUnlock()
+
+	value := underlying(w)
+	k, ok := kindOf(value)
+	if !ok {
+		return rosterEntry{}, fmt.Errorf("%w: %T (id %d)", ErrUnknownKind, value, e.id)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return rosterEntry{}, fmt.Errorf("company: encode worker %d: %w", e.id, err)
+	}
+	entry := rosterEntry{ID: e.id, Kind: k.name, Data: json.RawMessage(raw)}
+	if format == FormatYAML {
+		// YAML строится из того же JSON-представления, поэтому типам сотрудников
+		// достаточно уметь кодироваться в JSON
+		var generic interface{}
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return rosterEntry{}, fmt.Errorf("company: encode worker %d: %w", e.id, err)
+		}
+		entry.Data = generic
+	}
+	return entry, nil
+}
+
+// Import — заменяет список сотрудников сохранённым через Export.
+// Номера сотрудников и уволенных сохраняются; при ошибке компания не меняется
+func (c *Company) Import(r io.Reader, format Format) error {
+	var file rosterFile
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		if err := dec.Decode(&file); err != nil {
+			return fmt.Errorf("company: decode roster: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.NewDecoder(r).Decode(&file); err != nil {
+			return fmt.Errorf("company: decode roster: %w", err)
+		}
+	default:
+		return fmt.Errorf("company: unsupported format %d", format)
+	}
+
+	var (
+		personal = make([]*employee, 0, len(file.Workers))
+		byID     = make(map[int]*employee, len(file.Workers))
+		fired    = make(map[int]bool, len(file.Fired))
+		nextID   = file.NextID
+	)
+	for _, id := range file.Fired {
+		fired[id] = true
+		if id >= nextID {
+			nextID = id + 1
+		}
+	}
+	for _, entry := range file.Workers {
+		if _, dup := byID[entry.ID]; dup || fired[entry.ID] {
+			return fmt.Errorf("company: decode roster: duplicate id %d", entry.ID)
+		}
+		w, err := decodeWorker(entry)
+		if err != nil {
+			return err
+		}
+		e := &employee{id: entry.ID, worker: w}
+		personal = append(personal, e)
+		byID[e.id] = e
+		if e.id >= nextID {
+			nextID = e.id + 1
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.personal, c.byID, c.fired, c.nextID = personal, byID, fired, nextID
+	return nil
+}
+
+func decodeWorker(entry rosterEntry) (Worker, error) {
+	registry.RLock()
+	k, ok := registry.byName[entry.Kind]
+	registry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (id %d)", ErrUnknownKind, entry.Kind, entry.ID)
+	}
+
+	raw, err := json.Marshal(entry.Data)
+	if err != nil {
+		return nil, fmt.Errorf("company: decode worker %d: %w", entry.ID, err)
+	}
+	// для типа-указателя, как *robot.Robot, создаём сам объект, для типа-значения — указатель на него
+	var ptr reflect.Value
+	if k.typ.Kind() == reflect.Ptr {
+		ptr = reflect.New(k.typ.Elem())
+	} else {
+		ptr = reflect.New(k.typ)
+	}
+	if err := json.Unmarshal(raw, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("company: decode worker %d: %w", entry.ID, err)
+	}
+	value := ptr
+	if k.typ.Kind() != reflect.Ptr {
+		value = ptr.Elem()
+	}
+
+	if k.legacy {
+		return Adapt(value.Interface().(LegacyWorker)), nil
+	}
+	return value.Interface().(Worker), nil
+}
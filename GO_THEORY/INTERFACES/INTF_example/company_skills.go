@@ -0,0 +1,100 @@
+package company
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNoQualifiedWorker — ни у одного сотрудника нет всех навыков, которые нужны задаче
+var ErrNoQualifiedWorker = errors.New("company: no qualified worker")
+
+// Task — задача вместе с навыками, которые нужны для её выполнения
+type Task struct {
+	Name     string
+	Requires []string
+}
+
+// Skilled — сотрудник, который сообщает о своих навыках и о том,
+// сколько задач готов держать одновременно (в работе и в очереди).
+// Сотрудник без Skilled не владеет никакими навыками и берёт только задачи без требований
+type Skilled interface {
+	Skills() []string
+	// Capacity — предел нагрузки для стратегии BestMatch; 0 — без ограничения
+	Capacity() int
+}
+
+// profile — навыки и допустимая нагрузка работника
+func profile(w Worker) (map[string]bool, int) {
+	s, ok := underlying(w).(Skilled)
+	if !ok {
+		return nil, 0
+	}
+	skills := make(map[string]bool)
+	for _, skill := range s.Skills() {
+		skills[skill] = true
+	}
+	return skills, s.Capacity()
+}
+
+// qualifies — есть ли среди skills все навыки, нужные задаче
+func qualifies(skills map[string]bool, task Task) bool {
+	for _, req := range task.Requires {
+		if !skills[req] {
+			return false
+		}
+	}
+	return true
+}
+
+func (pw *poolWorker) qualified(task Task) bool {
+	return qualifies(pw.skills, task)
+}
+
+// busy — сотрудник набрал предельную нагрузку
+func (pw *poolWorker) busy() bool {
+	return pw.capacity > 0 && atomic.LoadInt64(&pw.load) >= int64(pw.capacity)
+}
+
+// bestMatch — подходящий сотрудник с наименьшим числом лишних навыков, который ещё не занят.
+// Если заняты все, задачу получит наименее загруженный
+func bestMatch(candidates []*poolWorker) *poolWorker {
+	var best *poolWorker
+	for _, pw := range candidates {
+		if pw.busy() {
+			continue
+		}
+		if best == nil || len(pw.skills) < len(best.skills) {
+			best = pw
+		}
+	}
+	if best == nil {
+		return leastLoaded(candidates)
+	}
+	return best
+}
+
+// Unassignable — задачи, которые в компании некому выполнить: ни у кого из работающих
+// сотрудников нет всех нужных навыков. Удобно проверить список до DispatchTasks
+func (c *Company) Unassignable(tasks []Task) []Task {
+	c.mu.RLock()
+	profiles := make([]map[string]bool, len(c.personal))
+	for i, e := range c.personal {
+		profiles[i], _ = profile(e.worker)
+	}
+	c.mu.RUnlock()
+
+	var res []Task
+	for _, task := range tasks {
+		ok := false
+		for _, skills := range profiles {
+			if qualifies(skills, task) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			res = append(res, task)
+		}
+	}
+	return res
+}
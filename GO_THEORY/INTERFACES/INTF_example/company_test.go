@@ -0,0 +1,624 @@
+package company
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWorker — сотрудник для тестов: работает delay и считает выполненные задачи
+type fakeWorker struct {
+	name  string
+	delay time.Duration
+
+	mu      sync.Mutex
+	done    int
+	active  int
+	overlap bool
+}
+
+func (w *fakeWorker) Do(ctx context.Context, task string) TaskResult {
+	w.mu.Lock()
+	w.active++
+	if w.active > 1 {
+		w.overlap = true
+	}
+	w.mu.Unlock()
+
+	time.Sleep(w.delay)
+
+	w.mu.Lock()
+	w.active--
+	w.done++
+	w.mu.Unlock()
+	return TaskResult{Task: task, Status: StatusDone, Output: w.name + " did " + task}
+}
+
+func (w *fakeWorker) String() string {
+	return w.name
+}
+
+func collect(t *testing.T, ch <-chan Result) []Result {
+	t.Helper()
+	var res []Result
+	for r := range ch {
+		res = append(res, r)
+	}
+	return res
+}
+
+func TestDispatchRoundRobin(t *testing.T) {
+	a, b := &fakeWorker{name: "a"}, &fakeWorker{name: "b"}
+	c := Company{}
+	c.Hire(a)
+	c.Hire(b)
+
+	tasks := []string{"t0", "t1", "t2", "t3", "t4"}
+	ch, err := c.Dispatch(context.Background(), tasks, DispatchOptions{Strategy: RoundRobin, QueueSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := collect(t, ch)
+	if len(results) != len(tasks) {
+		t.Fatalf("expected %d results; got: %d", len(tasks), len(results))
+	}
+	for _, r := range results {
+		if r.Err != nil || r.Status != StatusDone {
+			t.Errorf("task %s: unexpected status %s: %v", r.Task, r.Status, r.Err)
+		}
+		if expected := r.Index % 2; r.WorkerID != expected {
+			t.Errorf("task %s: expected worker %d; got: %d", r.Task, expected, r.WorkerID)
+		}
+		if r.Output != r.Worker+" did "+r.Task {
+			t.Errorf("unexpected output %q from %s", r.Output, r.Worker)
+		}
+	}
+	if a.done != 3 || b.done != 2 {
+		t.Errorf("expected 3 and 2 tasks; got: %d and %d", a.done, b.done)
+	}
+}
+
+func TestDispatchLeastLoaded(t *testing.T) {
+	slow := &fakeWorker{name: "slow", delay: 50 * time.Millisecond}
+	fast := &fakeWorker{name: "fast"}
+	c := Company{}
+	c.Hire(slow)
+	c.Hire(fast)
+
+	tasks := make([]string, 20)
+	for i := range tasks {
+		tasks[i] = "task"
+	}
+	ch, err := c.Dispatch(context.Background(), tasks, DispatchOptions{Strategy: LeastLoaded, QueueSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	collect(t, ch)
+	if fast.done <= slow.done {
+		t.Errorf("least loaded must favour the fast worker: fast %d, slow %d", fast.done, slow.done)
+	}
+	if slow.overlap || fast.overlap {
+		t.Error("worker must not run tasks concurrently")
+	}
+}
+
+func TestDispatchTimeout(t *testing.T) {
+	w := &fakeWorker{name: "sleepy", delay: 100 * time.Millisecond}
+	c := Company{}
+	c.Hire(w)
+
+	start := time.Now()
+	ch, err := c.Dispatch(context.Background(), []string{"t0", "t1"}, DispatchOptions{TaskTimeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := <-ch
+	if !errors.Is(first.Err, context.DeadlineExceeded) || first.Status != StatusCanceled {
+		t.Errorf("expected deadline exceeded; got: %v", first.Err)
+	}
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Errorf("timed out result must arrive before the task finishes; got after %v", elapsed)
+	}
+	collect(t, ch)
+	if w.overlap {
+		t.Error("next task must wait for the timed out one")
+	}
+}
+
+func TestDispatchCancelAndEmpty(t *testing.T) {
+	c := Company{}
+	if _, err := c.Dispatch(context.Background(), []string{"t"}, DispatchOptions{}); !errors.Is(err, ErrNoWorkers) {
+		t.Errorf("expected ErrNoWorkers; got: %v", err)
+	}
+
+	c.Hire(&fakeWorker{name: "a"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ch, err := c.Dispatch(ctx, []string{"t0", "t1"}, DispatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range collect(t, ch) {
+		if !errors.Is(r.Err, context.Canceled) || r.WorkerID != -1 || r.Status != StatusCanceled {
+			t.Errorf("expected cancelled undispatched task; got: %+v", r)
+		}
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	c := Company{}
+	a := c.Hire(&fakeWorker{name: "a"})
+	b := c.Hire(&fakeWorker{name: "b"})
+
+	if out, err := c.Process(b, []string{"x"}); err != nil || len(out) != 1 || out[0].Output != "b did x" {
+		t.Errorf("expected b did x; got: %+v, %v", out, err)
+	}
+	if err := c.Fire(a); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.Process(a, []string{"x"})
+	var we *WorkerError
+	if !errors.Is(err, ErrWorkerFired) || !errors.As(err, &we) || we.ID != a {
+		t.Errorf("expected fired worker error for %d; got: %v", a, err)
+	}
+	if _, err := c.Lookup(42); !errors.Is(err, ErrUnknownWorker) {
+		t.Errorf("expected unknown worker error; got: %v", err)
+	}
+	if err := c.Fire(a); !errors.Is(err, ErrWorkerFired) {
+		t.Errorf("expected repeated fire to fail; got: %v", err)
+	}
+
+	if err := c.Replace(b, &fakeWorker{name: "b2"}); err != nil {
+		t.Fatal(err)
+	}
+	if w, err := c.Lookup(b); err != nil || w.(*fakeWorker).name != "b2" {
+		t.Errorf("expected replaced worker b2; got: %v, %v", w, err)
+	}
+	if d := c.Hire(&fakeWorker{name: "d"}); d == a || d == b {
+		t.Errorf("ids must not be reused; got: %d", d)
+	}
+
+	list := c.List()
+	if len(list) != 2 || list[0].ID != b || list[1].Worker.(*fakeWorker).name != "d" {
+		t.Errorf("unexpected roster: %+v", list)
+	}
+}
+
+func TestConcurrentCompany(t *testing.T) {
+	c := Company{}
+	w := &fakeWorker{name: "shared"}
+	id := c.Hire(w)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			extra := c.Hire(&fakeWorker{name: "tmp"})
+			if _, err := c.Process(id, []string{"x"}); err != nil {
+				t.Error(err)
+			}
+			c.List()
+			if i%2 == 0 {
+				if err := c.Fire(extra); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if w.overlap {
+		t.Error("Process must not run one worker concurrently")
+	}
+	if n := len(c.List()); n != 11 {
+		t.Errorf("expected 11 workers; got: %d", n)
+	}
+}
+
+// legacyWorker — работник со старым строковым контрактом
+type legacyWorker struct {
+	name  string
+	panic bool
+}
+
+func (w legacyWorker) Work(tasks []string) string {
+	if w.panic {
+		panic("out of order")
+	}
+	return w.name + " work:\n I do " + tasks[0]
+}
+
+func (w legacyWorker) String() string {
+	return w.name
+}
+
+func TestAdapt(t *testing.T) {
+	c := Company{}
+	ok := c.Hire(Adapt(legacyWorker{name: "bob"}))
+	broken := c.Hire(Adapt(legacyWorker{name: "rob", panic: true}))
+
+	res, err := c.Process(ok, []string{"dishes", "laundry"})
+	if err != nil || len(res) != 2 {
+		t.Fatalf("expected 2 results; got: %v, %v", res, err)
+	}
+	if res[1].Status != StatusDone || res[1].Output != "bob work:\n I do laundry" || res[1].Task != "laundry" {
+		t.Errorf("unexpected result: %+v", res[1])
+	}
+
+	res, _ = c.Process(broken, []string{"weld"})
+	if res[0].Status != StatusFailed || res[0].Err == nil {
+		t.Errorf("expected failed result; got: %+v", res[0])
+	}
+
+	ch, _ := c.Dispatch(context.Background(), []string{"a"}, DispatchOptions{})
+	if r := <-ch; r.Worker != "bob" {
+		t.Errorf("adapter must describe the wrapped worker; got: %q", r.Worker)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if r := Adapt(legacyWorker{}).Do(ctx, "x"); r.Status != StatusCanceled {
+		t.Errorf("expected canceled status; got: %s", r.Status)
+	}
+}
+
+// storedWorker и storedLegacy — сотрудники с открытыми полями, которых можно сохранить
+type storedWorker struct {
+	Name string `json:"name"`
+}
+
+func (w *storedWorker) Do(ctx context.Context, task string) TaskResult {
+	return TaskResult{Task: task, Status: StatusDone, Output: w.Name}
+}
+
+type storedLegacy struct {
+	Model  string `json:"model"`
+	Serial int    `json:"serial"`
+}
+
+func (w storedLegacy) Work(tasks []string) string {
+	return w.Model
+}
+
+func init() {
+	RegisterKind("stored", &storedWorker{})
+	RegisterKind("stored-legacy", storedLegacy{})
+}
+
+func TestRosterRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		c := Company{}
+		a := c.Hire(&storedWorker{Name: "alice"})
+		gone := c.Hire(&storedWorker{Name: "gone"})
+		b := c.Hire(Adapt(storedLegacy{Model: "T-800", Serial: 1 << 40}))
+		if err := c.Fire(gone); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := c.Export(&buf, format); err != nil {
+			t.Fatalf("format %d: export: %v", format, err)
+		}
+		saved := buf.String()
+
+		loaded := Company{}
+		if err := loaded.Import(&buf, format); err != nil {
+			t.Fatalf("format %d: import: %v\n%s", format, err, saved)
+		}
+		if w, err := loaded.Lookup(a); err != nil || w.(*storedWorker).Name != "alice" {
+			t.Errorf("format %d: expected alice; got: %v, %v", format, w, err)
+		}
+		w, err := loaded.Lookup(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if legacy := w.(*Adapter).Legacy.(storedLegacy); legacy.Model != "T-800" || legacy.Serial != 1<<40 {
+			t.Errorf("format %d: unexpected legacy worker: %+v", format, legacy)
+		}
+		if _, err := loaded.Lookup(gone); !errors.Is(err, ErrWorkerFired) {
+			t.Errorf("format %d: fired worker must stay fired; got: %v", format, err)
+		}
+		if id := loaded.Hire(&storedWorker{}); id <= b {
+			t.Errorf("format %d: ids must continue after %d; got: %d", format, b, id)
+		}
+	}
+}
+
+func TestRosterPointerKind(t *testing.T) {
+	c := Company{}
+	// тип зарегистрирован значением, а нанят указатель — это тот же тип
+	id := c.Hire(Adapt(&storedLegacy{Model: "T-1000"}))
+	var buf bytes.Buffer
+	if err := c.Export(&buf, FormatJSON); err != nil {
+		t.Fatalf("pointer to a registered type must be exported: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"kind": "stored-legacy"`) {
+		t.Errorf("expected kind stored-legacy; got: %s", buf.String())
+	}
+	loaded := Company{}
+	if err := loaded.Import(&buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	w, err := loaded.Lookup(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if legacy, ok := w.(*Adapter).Legacy.(storedLegacy); !ok || legacy.Model != "T-1000" {
+		t.Errorf("expected worker loaded as registered; got: %#v", w.(*Adapter).Legacy)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a pointer to a registered type must panic")
+		}
+	}()
+	RegisterKind("stored-legacy-ptr", &storedLegacy{})
+}
+
+func TestRosterUnknownKind(t *testing.T) {
+	c := Company{}
+	c.Hire(&fakeWorker{name: "unregistered"})
+	if err := c.Export(io.Discard, FormatJSON); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("expected ErrUnknownKind on export; got: %v", err)
+	}
+
+	in := `{"next_id": 1, "workers": [{"id": 0, "kind": "dog", "data": {}}]}`
+	if err := c.Import(strings.NewReader(in), FormatJSON); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("expected ErrUnknownKind on import; got: %v", err)
+	}
+	if len(c.List()) != 1 {
+		t.Error("failed import must not change the company")
+	}
+}
+
+// flakyWorker — сотрудник, который бывает недоступен до ремонта
+type flakyWorker struct {
+	fakeWorker
+	down bool
+}
+
+func (w *flakyWorker) Available() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return !w.down
+}
+
+func (w *flakyWorker) Repair() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.down = false
+}
+
+func TestDispatchSkipsUnavailable(t *testing.T) {
+	for _, strategy := range []Strategy{RoundRobin, LeastLoaded} {
+		c := Company{}
+		broken := &flakyWorker{fakeWorker: fakeWorker{name: "broken"}, down: true}
+		ok := &fakeWorker{name: "ok"}
+		brokenID := c.Hire(broken)
+		c.Hire(ok)
+
+		ch, _ := c.Dispatch(context.Background(), []string{"a", "b", "c"}, DispatchOptions{Strategy: strategy})
+		collect(t, ch)
+		if broken.done != 0 || ok.done != 3 {
+			t.Errorf("strategy %d: expected all tasks on ok; got broken %d, ok %d", strategy, broken.done, ok.done)
+		}
+
+		if err := c.Fire(1); err != nil {
+			t.Fatal(err)
+		}
+		ch, _ = c.Dispatch(context.Background(), []string{"a"}, DispatchOptions{Strategy: strategy})
+		if r := <-ch; r.Status != StatusFailed || !errors.Is(r.Err, ErrNoAvailableWorkers) {
+			t.Errorf("strategy %d: expected ErrNoAvailableWorkers; got: %+v", strategy, r)
+		}
+
+		if err := c.Repair(brokenID); err != nil {
+			t.Fatal(err)
+		}
+		ch, _ = c.Dispatch(context.Background(), []string{"a"}, DispatchOptions{Strategy: strategy})
+		if r := <-ch; r.Err != nil || r.WorkerID != brokenID {
+			t.Errorf("strategy %d: repaired worker must get work; got: %+v", strategy, r)
+		}
+	}
+}
+
+func TestRepairNotRepairable(t *testing.T) {
+	c := Company{}
+	id := c.Hire(Adapt(legacyWorker{name: "bob"}))
+	if err := c.Repair(id); !errors.Is(err, ErrNotRepairable) {
+		t.Errorf("expected ErrNotRepairable; got: %v", err)
+	}
+}
+
+// specialist — сотрудник с навыками и ограничением нагрузки
+type specialist struct {
+	fakeWorker
+	skills   []string
+	capacity int
+}
+
+func (w *specialist) Skills() []string { return w.skills }
+func (w *specialist) Capacity() int    { return w.capacity }
+
+func TestDispatchSkills(t *testing.T) {
+	c := Company{}
+	c.Hire(&fakeWorker{name: "intern"})
+	welderID := c.Hire(&specialist{fakeWorker: fakeWorker{name: "welder"}, skills: []string{"weld"}})
+	c.Hire(&specialist{fakeWorker: fakeWorker{name: "master"}, skills: []string{"weld", "paint", "drive"}})
+
+	tasks := []Task{
+		{Name: "sweep"},
+		{Name: "weld pipe", Requires: []string{"weld"}},
+		{Name: "paint car", Requires: []string{"paint", "drive"}},
+		{Name: "fly", Requires: []string{"pilot"}},
+	}
+	if got := c.Unassignable(tasks); len(got) != 1 || got[0].Name != "fly" {
+		t.Errorf("expected only fly to be unassignable; got: %v", got)
+	}
+
+	for _, strategy := range []Strategy{RoundRobin, LeastLoaded, BestMatch} {
+		ch, err := c.DispatchTasks(context.Background(), tasks, DispatchOptions{Strategy: strategy, QueueSize: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range collect(t, ch) {
+			switch r.Task {
+			case "weld pipe":
+				if r.Worker != "welder" && r.Worker != "master" {
+					t.Errorf("strategy %d: weld given to %s", strategy, r.Worker)
+				}
+				if strategy == BestMatch && r.WorkerID != welderID {
+					t.Errorf("best match must prefer the narrow specialist; got: %s", r.Worker)
+				}
+			case "paint car":
+				if r.Worker != "master" {
+					t.Errorf("strategy %d: paint given to %s", strategy, r.Worker)
+				}
+			case "fly":
+				if r.WorkerID != -1 || r.Status != StatusFailed || !errors.Is(r.Err, ErrNoQualifiedWorker) {
+					t.Errorf("strategy %d: expected ErrNoQualifiedWorker; got: %+v", strategy, r)
+				}
+			}
+		}
+	}
+}
+
+func TestDispatchBestMatchFallback(t *testing.T) {
+	c := Company{}
+	narrow := &specialist{fakeWorker: fakeWorker{name: "narrow", delay: 20 * time.Millisecond}, skills: []string{"weld"}, capacity: 1}
+	broad := &specialist{fakeWorker: fakeWorker{name: "broad", delay: 20 * time.Millisecond}, skills: []string{"weld", "paint"}}
+	c.Hire(narrow)
+	c.Hire(broad)
+
+	tasks := []Task{{Name: "a", Requires: []string{"weld"}}, {Name: "b", Requires: []string{"weld"}}}
+	ch, err := c.DispatchTasks(context.Background(), tasks, DispatchOptions{Strategy: BestMatch, QueueSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	collect(t, ch)
+	if narrow.done != 1 || broad.done != 1 {
+		t.Errorf("busy specialist must pass work on: narrow %d, broad %d", narrow.done, broad.done)
+	}
+
+	narrowDown := &flakyWorker{fakeWorker: fakeWorker{name: "down"}, down: true}
+	c = Company{}
+	c.Hire(narrowDown)
+	ch, _ = c.DispatchTasks(context.Background(), []Task{{Name: "x"}}, DispatchOptions{Strategy: BestMatch})
+	if r := <-ch; !errors.Is(r.Err, ErrNoAvailableWorkers) {
+		t.Errorf("expected ErrNoAvailableWorkers; got: %+v", r)
+	}
+}
+
+// hourlyWorker и pieceWorker — сотрудники с почасовой и сдельной оплатой
+type hourlyWorker struct {
+	fakeWorker
+	rate float64
+}
+
+func (w *hourlyWorker) HourlyRate() float64 { return w.rate }
+
+type pieceWorker struct {
+	flakyWorker
+	perTask, depreciation float64
+}
+
+func (w *pieceWorker) TaskRate() float64         { return w.perTask }
+func (w *pieceWorker) DepreciationRate() float64 { return w.depreciation }
+
+func (w *pieceWorker) Do(ctx context.Context, task string) TaskResult {
+	if !w.Available() {
+		return TaskResult{Task: task, Status: StatusFailed, Err: errors.New("down")}
+	}
+	return w.fakeWorker.Do(ctx, task)
+}
+
+// fakeClock — часы, которые при каждом обращении уходят вперёд на step
+func fakeClock(start time.Time, step time.Duration) func() time.Time {
+	var mu sync.Mutex
+	t := start
+	return func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		t = t.Add(step)
+		return t
+	}
+}
+
+func TestPayroll(t *testing.T) {
+	day := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	defer func(f func() time.Time) { now = f }(now)
+	now = fakeClock(day, 30*time.Minute)
+
+	c := Company{}
+	annID := c.Hire(&hourlyWorker{fakeWorker: fakeWorker{name: "ann"}, rate: 20})
+	bot := &pieceWorker{flakyWorker: flakyWorker{fakeWorker: fakeWorker{name: "bot"}}, perTask: 1.5, depreciation: 4}
+	botID := c.Hire(bot)
+	c.Hire(&fakeWorker{name: "volunteer"})
+
+	// каждая задача занимает ровно полчаса: start и конец — два соседних обращения к часам
+	if _, err := c.Process(annID, []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Process(botID, []string{"c"}); err != nil {
+		t.Fatal(err)
+	}
+	bot.mu.Lock()
+	bot.down = true
+	bot.mu.Unlock()
+	if _, err := c.Process(botID, []string{"d"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Process(2, []string{"e"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Fire(annID); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(c.Timesheet(time.Time{}, time.Time{})); got != 5 {
+		t.Fatalf("expected 5 timesheet entries; got: %d", got)
+	}
+	p := c.Payroll(day, day.Add(24*time.Hour))
+	if len(p.Workers) != 3 {
+		t.Fatalf("expected 3 workers, fired included; got: %+v", p.Workers)
+	}
+	ann, b := p.Workers[0], p.Workers[1]
+	if ann.Hours != time.Hour || ann.Labor != 20 || ann.Depreciation != 0 {
+		t.Errorf("unexpected pay for ann: %+v", ann)
+	}
+	// сдельная плата только за выполненную задачу, износ — за всё время работы
+	if b.Tasks != 2 || b.Done != 1 || b.Labor != 1.5 || b.Depreciation != 4 {
+		t.Errorf("unexpected pay for bot: %+v", b)
+	}
+	if p.Total.Total() != 25.5 || p.Total.Tasks != 5 {
+		t.Errorf("expected total 25.5 for 5 tasks; got: %+v", p.Total)
+	}
+	if len(p.Kinds) != 3 {
+		t.Errorf("expected 3 kinds; got: %+v", p.Kinds)
+	}
+
+	if empty := c.Payroll(day.Add(24*time.Hour), time.Time{}); len(empty.Workers) != 0 || empty.Total.Total() != 0 {
+		t.Errorf("expected empty payroll for the next day; got: %+v", empty)
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1+3+3+1 {
+		t.Fatalf("unexpected CSV:\n%s", buf.String())
+	}
+	if expected := "worker,0,ann,*company.hourlyWorker,2,2,1.0000,20.00,0.00,20.00"; lines[1] != expected {
+		t.Errorf("expected %q; got: %q", expected, lines[1])
+	}
+	if expected := "total,,,,5,4,2.5000,21.50,4.00,25.50"; lines[len(lines)-1] != expected {
+		t.Errorf("expected %q; got: %q", expected, lines[len(lines)-1])
+	}
+}
@@ -0,0 +1,134 @@
+package company
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Status — чем закончилась задача
+type Status int
+
+const (
+	// StatusDone — задача выполнена
+	StatusDone Status = iota
+	// StatusFailed — сотрудник не справился, причина в Err
+	StatusFailed
+	// StatusCanceled — задача отменена или не уложилась в отведённое время, причина в Err
+	StatusCanceled
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusDone:
+		return "done"
+	case StatusFailed:
+		return "failed"
+	case StatusCanceled:
+		return "canceled"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// TaskResult — итог одной задачи: статус, результат работы, ошибка и затраченное время
+type TaskResult struct {
+	Task     string
+	Status   Status
+	Output   string
+	Err      error
+	Duration time.Duration
+}
+
+// Worker — интерфейс работника компании
+type Worker interface {
+	// всё, что он должен уметь делать, — это работать: выполнить задачу и отчитаться о результате
+	Do(ctx context.Context, task string) TaskResult
+}
+
+// LegacyWorker — прежний контракт работника, который отчитывается о работе одной строкой.
+// Его реализуют person.Person и *robot.Robot; нанять такого работника можно через Adapt
+type LegacyWorker interface {
+	Work(tasks []string) string
+}
+
+// FallibleWorker — старый контракт, дополненный ошибкой. Если работник его реализует,
+// Adapter вызывает TryWork вместо Work и отмечает задачу как неудавшуюся
+type FallibleWorker interface {
+	TryWork(tasks []string) (string, error)
+}
+
+// Availability — сотрудник, который бывает временно недоступен, например сломанный робот.
+// Таким сотрудникам Dispatch не выдаёт задачи
+type Availability interface {
+	Available() bool
+}
+
+// Repairable — сотрудник, которого можно вернуть в строй через Company.Repair
+type Repairable interface {
+	Repair()
+}
+
+// Adapter — приводит LegacyWorker к интерфейсу Worker
+type Adapter struct {
+	Legacy LegacyWorker
+}
+
+// Adapt — оборачивает работника со старым контрактом
+//
+//	comp.Hire(company.Adapt(person.Person{}))
+func Adapt(w LegacyWorker) Worker {
+	return &Adapter{Legacy: w}
+}
+
+// Do — выполняет задачу через Work. Строка, которую вернул Work, становится Output.
+// Старый контракт не умеет сообщать об ошибках, поэтому неудачей считается паника
+// или ошибка TryWork, если работник реализует FallibleWorker
+func (a *Adapter) Do(ctx context.Context, task string) (res TaskResult) {
+	res = TaskResult{Task: task}
+	if err := ctx.Err(); err != nil {
+		res.Status, res.Err = StatusCanceled, err
+		return res
+	}
+
+	start := time.Now()
+	defer func() {
+		res.Duration = time.Since(start)
+		if r := recover(); r != nil {
+			res.Status, res.Err = StatusFailed, fmt.Errorf("company: worker panicked: %v", r)
+		}
+	}()
+	if fw, ok := a.Legacy.(FallibleWorker); ok {
+		out, err := fw.TryWork([]string{task})
+		res.Output = out
+		if err != nil {
+			res.Status, res.Err = StatusFailed, err
+			return res
+		}
+	} else {
+		res.Output = a.Legacy.Work([]string{task})
+	}
+	res.Status = StatusDone
+	return res
+}
+
+// String — описание обёрнутого работника
+func (a *Adapter) String() string {
+	return fmt.Sprint(a.Legacy)
+}
+
+// underlying — сам работник без обёртки Adapter; по нему проверяются необязательные интерфейсы
+func underlying(w Worker) interface{} {
+	if a, ok := w.(*Adapter); ok {
+		return a.Legacy
+	}
+	return w
+}
+
+// available — можно ли сейчас выдавать работнику задачи
+func available(w Worker) bool {
+	if av, ok := underlying(w).(Availability); ok {
+		return av.Available()
+	}
+	return true
+}
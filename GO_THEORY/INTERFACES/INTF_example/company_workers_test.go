@@ -0,0 +1,45 @@
+package company_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"company"
+	"person"
+	"robot"
+)
+
+func TestDispatchRealWorkers(t *testing.T) {
+	c := company.Company{}
+	alice := person.New("alice", "")
+	alice.SetSkills("write", "review")
+	welder := robot.New("W-1", 1)
+	welder.SetSkills("weld")
+	welder.SetCapacity(2)
+	aliceID := c.Hire(company.Adapt(alice))
+	welderID := c.Hire(company.Adapt(welder))
+
+	tasks := []company.Task{
+		{Name: "report", Requires: []string{"write"}},
+		{Name: "pipe", Requires: []string{"weld"}},
+		{Name: "fly", Requires: []string{"pilot"}},
+	}
+	if got := c.Unassignable(tasks); len(got) != 1 || got[0].Name != "fly" {
+		t.Errorf("expected only fly to be unassignable; got: %v", got)
+	}
+
+	ch, err := c.DispatchTasks(context.Background(), tasks, company.DispatchOptions{Strategy: company.BestMatch, QueueSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{"report": aliceID, "pipe": welderID, "fly": -1}
+	for r := range ch {
+		if r.WorkerID != expected[r.Task] {
+			t.Errorf("task %s: expected worker %d; got: %d (%s)", r.Task, expected[r.Task], r.WorkerID, r.Worker)
+		}
+		if r.Task == "fly" && !errors.Is(r.Err, company.ErrNoQualifiedWorker) {
+			t.Errorf("expected ErrNoQualifiedWorker; got: %v", r.Err)
+		}
+	}
+}
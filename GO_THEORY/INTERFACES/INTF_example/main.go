@@ -2,12 +2,36 @@ package main
 
 import (
 	"company"
+	"os"
 	"person"
+	"robot"
+	"time"
 )
 
 func main() {
 	pers := person.Person{}
+	pers.SetHourlyRate(15)
+	robo := &robot.Robot{}
+	robo.SetPricing(robot.Pricing{PerTask: 0.5, Depreciation: 2})
 	comp := company.Company{}
 
-	comp.Hire(pers) // мы передаём переменную типа Person в функцию, аргументом которой является переменная Worker!
+	// мы передаём переменную типа Person в функцию, аргументом которой является переменная LegacyWorker!
+	// Adapt приводит старый строковый контракт к новому интерфейсу Worker
+	persID := comp.Hire(company.Adapt(pers))
+	roboID := comp.Hire(company.Adapt(robo))
+
+	// чтобы сохранять сотрудников, компании нужно знать их типы; сами пакеты person и robot о компании ничего не знают
+	company.RegisterKind("person", person.Person{})
+	company.RegisterKind("robot", &robot.Robot{})
+	if err := comp.Export(os.Stdout, company.FormatYAML); err != nil {
+		panic(err)
+	}
+
+	// время работы попадает в табель, а из него — в ведомость
+	start := time.Now()
+	comp.Process(persID, []string{"write report"})
+	comp.Process(roboID, []string{"weld", "paint"})
+	if err := comp.Payroll(start, time.Time{}).WriteCSV(os.Stdout); err != nil {
+		panic(err)
+	}
 }
@@ -1,17 +1,63 @@
 package person
 
+import "encoding/json"
+
 // структура, описывающая человека
 type Person struct {
 	name     string
 	homework string
 	children []*Person
+	// parent — родитель в семейном дереве; nil у корня
+	parent *Person
+	// submissions — сданные домашние работы в порядке сдачи
+	submissions []*Submission
+	// rate — почасовая ставка
+	rate float64
+	// skills — навыки, capacity — сколько задач человек готов держать одновременно; 0 — без ограничения
+	skills   []string
+	capacity int
+}
+
+// New — создаёт человека с именем name
+func New(name, homework string) *Person {
+	return &Person{name: name, homework: homework}
 }
 
-// DoHomework — делает домашнюю работу
+// DoHomework — делает домашнюю работу: возвращает последнюю сданную работу
 func (p Person) DoHomework() string {
 	return p.homework
 }
 
+// HourlyRate — почасовая ставка; по ней компания оплачивает время работы
+func (p Person) HourlyRate() float64 {
+	return p.rate
+}
+
+// SetHourlyRate — назначает почасовую ставку
+func (p *Person) SetHourlyRate(rate float64) {
+	p.rate = rate
+}
+
+// Skills — навыки человека; по ним компания решает, какие задачи ему можно поручить
+func (p Person) Skills() []string {
+	return append([]string(nil), p.skills...)
+}
+
+// SetSkills — задаёт навыки человека
+func (p *Person) SetSkills(skills ...string) {
+	p.skills = append([]string(nil), skills...)
+}
+
+// Capacity — сколько задач человек готов держать одновременно; 0 — без ограничения
+func (p Person) Capacity() int {
+	return p.capacity
+}
+
+// SetCapacity — задаёт, сколько задач человек готов держать одновременно
+func (p *Person) SetCapacity(n int) {
+	p.capacity = n
+}
+
 // Children — сообщает информацию о детях
 func (p Person) Children() []*Person {
 	return p.children
@@ -30,3 +76,38 @@ func (p Person) Work(tasks []string) string {
 func (p Person) String() string {
 	return p.name
 }
+
+// personJSON — представление Person в JSON; поля Person закрыты, поэтому нужен отдельный тип
+type personJSON struct {
+	Name        string        `json:"name"`
+	Homework    string        `json:"homework,omitempty"`
+	Children    []*Person     `json:"children,omitempty"`
+	Submissions []*Submission `json:"submissions,omitempty"`
+	HourlyRate  float64       `json:"hourly_rate,omitempty"`
+	Skills      []string      `json:"skills,omitempty"`
+	Capacity    int           `json:"capacity,omitempty"`
+}
+
+// MarshalJSON — сохраняет человека вместе с детьми
+func (p Person) MarshalJSON() ([]byte, error) {
+	return json.Marshal(personJSON{
+		Name: p.name, Homework: p.homework, Children: p.children, Submissions: p.submissions,
+		HourlyRate: p.rate, Skills: p.skills, Capacity: p.capacity,
+	})
+}
+
+// UnmarshalJSON — восстанавливает человека, сохранённого через MarshalJSON
+func (p *Person) UnmarshalJSON(data []byte) error {
+	var v personJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	p.name, p.homework, p.children, p.submissions, p.rate = v.Name, v.Homework, v.Children, v.Submissions, v.HourlyRate
+	p.skills, p.capacity = v.Skills, v.Capacity
+	for _, c := range p.children {
+		if c != nil {
+			c.parent = p
+		}
+	}
+	return nil
+}
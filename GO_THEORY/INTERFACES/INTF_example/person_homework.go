@@ -0,0 +1,223 @@
+package person
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnknownCriterion — проверяющий поставил баллы за критерий, которого нет в рубрике
+	ErrUnknownCriterion = errors.New("person: unknown rubric criterion")
+	// ErrScoreOutOfRange — баллы за критерий меньше нуля или больше максимума
+	ErrScoreOutOfRange = errors.New("person: score out of range")
+)
+
+// now — текущее время; в тестах подменяется, чтобы сдача работ не зависела от часов
+var now = time.Now
+
+// Criterion — критерий рубрики и максимальный балл за него
+type Criterion struct {
+	Name      string  `json:"name"`
+	MaxPoints float64 `json:"max_points"`
+}
+
+// Assignment — домашнее задание
+type Assignment struct {
+	ID     string      `json:"id"`
+	Title  string      `json:"title"`
+	Due    time.Time   `json:"due"`
+	Rubric []Criterion `json:"rubric"`
+	// LatePenalty — доля итоговой оценки, которая снимается за каждые начатые сутки опоздания:
+	// 0.1 — минус 10% в день. Больше 100% снять нельзя
+	LatePenalty float64 `json:"late_penalty,omitempty"`
+}
+
+// MaxPoints — максимально возможная оценка за задание
+func (a Assignment) MaxPoints() float64 {
+	var res float64
+	for _, c := range a.Rubric {
+		res += c.MaxPoints
+	}
+	return res
+}
+
+// Submission — сданная работа
+type Submission struct {
+	Assignment  Assignment `json:"assignment"`
+	Content     string     `json:"content"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	// Grade — оценка; nil, пока работа не проверена
+	Grade *Grade `json:"grade,omitempty"`
+}
+
+// Late — на сколько работа сдана позже срока; 0, если вовремя
+func (s Submission) Late() time.Duration {
+	if d := s.SubmittedAt.Sub(s.Assignment.Due); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Grade — оценка за работу
+type Grade struct {
+	// Scores — баллы по критериям рубрики
+	Scores map[string]float64 `json:"scores"`
+	// Raw — сумма баллов до штрафа за опоздание
+	Raw float64 `json:"raw"`
+	// Penalty — доля, снятая за опоздание
+	Penalty float64 `json:"penalty,omitempty"`
+	// Final — итоговая оценка: Raw * (1 - Penalty)
+	Final    float64   `json:"final"`
+	Max      float64   `json:"max"`
+	Comment  string    `json:"comment,omitempty"`
+	GradedAt time.Time `json:"graded_at"`
+}
+
+// Grader — проверяющий. Это может быть автоматическая проверка или преподаватель
+type Grader interface {
+	// Grade — выставляет баллы по критериям рубрики; критерии без баллов получают 0
+	Grade(a Assignment, content string) (scores map[string]float64, comment string, err error)
+}
+
+// GraderFunc — позволяет использовать обычную функцию как Grader
+type GraderFunc func(a Assignment, content string) (map[string]float64, string, error)
+
+func (f GraderFunc) Grade(a Assignment, content string) (map[string]float64, string, error) {
+	return f(a, content)
+}
+
+// Submit — сдаёт работу по заданию и запоминает время сдачи.
+// Сданная работа становится тем, что возвращает DoHomework
+func (p *Person) Submit(a Assignment, content string) *Submission {
+	s := &Submission{Assignment: a, Content: content, SubmittedAt: now()}
+	p.submissions = append(p.submissions, s)
+	p.homework = content
+	return s
+}
+
+// Submissions — все сданные работы в порядке сдачи
+func (p Person) Submissions() []*Submission {
+	return p.submissions
+}
+
+// GradePending — проверяет все ещё не проверенные работы.
+// На первой ошибке останавливается; уже выставленные оценки сохраняются
+func (p *Person) GradePending(g Grader) error {
+	for _, s := range p.submissions {
+		if s.Grade != nil {
+			continue
+		}
+		grade, err := Evaluate(s, g)
+		if err != nil {
+			return err
+		}
+		s.Grade = &grade
+	}
+	return nil
+}
+
+// Evaluate — оценивает работу: проверяет баллы по рубрике и применяет штраф за опоздание
+func Evaluate(s *Submission, g Grader) (Grade, error) {
+	a := s.Assignment
+	scores, comment, err := g.Grade(a, s.Content)
+	if err != nil {
+		return Grade{}, fmt.Errorf("person: grade %s: %w", a.ID, err)
+	}
+
+	grade := Grade{Scores: make(map[string]float64, len(a.Rubric)), Max: a.MaxPoints(), Comment: comment, GradedAt: now()}
+	limits := make(map[string]float64, len(a.Rubric))
+	for _, c := range a.Rubric {
+		limits[c.Name] = c.MaxPoints
+		grade.Scores[c.Name] = 0
+	}
+	for name, points := range scores {
+		limit, ok := limits[name]
+		if !ok {
+			return Grade{}, fmt.Errorf("%w: %s: %q", ErrUnknownCriterion, a.ID, name)
+		}
+		if math.IsNaN(points) || points < 0 || points > limit {
+			return Grade{}, fmt.Errorf("%w: %s: %q got %g of %g", ErrScoreOutOfRange, a.ID, name, points, limit)
+		}
+		grade.Scores[name] = points
+		grade.Raw += points
+	}
+
+	if late := s.Late(); late > 0 && a.LatePenalty > 0 {
+		days := math.Ceil(late.Hours() / 24)
+		grade.Penalty = math.Min(1, days*a.LatePenalty)
+	}
+	grade.Final = grade.Raw * (1 - grade.Penalty)
+	return grade, nil
+}
+
+// Report — успеваемость человека: оценки в порядке сдачи работ
+type Report struct {
+	Name    string
+	Entries []ReportEntry
+	// Average — средний процент по проверенным работам
+	Average float64
+}
+
+// ReportEntry — строка отчёта об одной работе
+type ReportEntry struct {
+	AssignmentID string
+	Title        string
+	SubmittedAt  time.Time
+	Late         time.Duration
+	// Grade — nil, пока работа не проверена
+	Grade *Grade
+}
+
+// Percent — итоговая оценка в процентах от максимума; 0 для непроверенной работы
+func (e ReportEntry) Percent() float64 {
+	if e.Grade == nil || e.Grade.Max == 0 {
+		return 0
+	}
+	return 100 * e.Grade.Final / e.Grade.Max
+}
+
+// Report — собирает отчёт об успеваемости
+func (p Person) Report() Report {
+	r := Report{Name: p.name}
+	graded := 0
+	for _, s := range p.submissions {
+		e := ReportEntry{
+			AssignmentID: s.Assignment.ID,
+			Title:        s.Assignment.Title,
+			SubmittedAt:  s.SubmittedAt,
+			Late:         s.Late(),
+			Grade:        s.Grade,
+		}
+		if e.Grade != nil {
+			r.Average += e.Percent()
+			graded++
+		}
+		r.Entries = append(r.Entries, e)
+	}
+	if graded > 0 {
+		r.Average /= float64(graded)
+	}
+	return r
+}
+
+// String — отчёт в виде текстовой таблицы
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: average %.1f%%\n", r.Name, r.Average)
+	for _, e := range r.Entries {
+		fmt.Fprintf(&b, "%s  %-10s ", e.SubmittedAt.Format("2006-01-02 15:04"), e.AssignmentID)
+		if e.Grade == nil {
+			b.WriteString("not graded")
+		} else {
+			fmt.Fprintf(&b, "%5.1f/%-5.1f %5.1f%%", e.Grade.Final, e.Grade.Max, e.Percent())
+		}
+		if e.Late > 0 {
+			fmt.Fprintf(&b, "  late %s", e.Late.Round(time.Minute))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,245 @@
+package person
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func names(people []*Person) []string {
+	res := make([]string, len(people))
+	for i, p := range people {
+		res[i] = p.name
+	}
+	return res
+}
+
+// family — дерево из трёх поколений:
+//
+//	grandma -> mom -> (alice, bob), grandma -> uncle -> carl
+func family(t *testing.T) map[string]*Person {
+	t.Helper()
+	people := map[string]*Person{}
+	for _, name := range []string{"grandma", "mom", "uncle", "alice", "bob", "carl"} {
+		people[name] = New(name, "")
+	}
+	link := func(parent, child string) {
+		if err := people[parent].AddChild(people[child]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link("grandma", "mom")
+	link("grandma", "uncle")
+	link("mom", "alice")
+	link("mom", "bob")
+	link("uncle", "carl")
+	return people
+}
+
+func TestTreeQueries(t *testing.T) {
+	f := family(t)
+
+	if got := names(f["grandma"].Descendants()); !equal(got, []string{"mom", "alice", "bob", "uncle", "carl"}) {
+		t.Errorf("unexpected descendants: %v", got)
+	}
+	if got := names(f["bob"].Ancestors()); !equal(got, []string{"mom", "grandma"}) {
+		t.Errorf("unexpected ancestors: %v", got)
+	}
+	if d := f["carl"].Depth(); d != 2 {
+		t.Errorf("expected depth 2; got: %d", d)
+	}
+	if lca := LowestCommonAncestor(f["alice"], f["bob"]); lca != f["mom"] {
+		t.Errorf("expected mom; got: %v", lca)
+	}
+	if lca := LowestCommonAncestor(f["alice"], f["carl"]); lca != f["grandma"] {
+		t.Errorf("expected grandma; got: %v", lca)
+	}
+	if lca := LowestCommonAncestor(f["mom"], f["bob"]); lca != f["mom"] {
+		t.Errorf("expected mom; got: %v", lca)
+	}
+	if lca := LowestCommonAncestor(f["alice"], New("stranger", "")); lca != nil {
+		t.Errorf("expected no common ancestor; got: %v", lca)
+	}
+}
+
+func TestAddChildRejectsCycles(t *testing.T) {
+	f := family(t)
+	if err := f["alice"].AddChild(f["grandma"]); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle; got: %v", err)
+	}
+	if err := f["uncle"].AddChild(f["bob"]); !errors.Is(err, ErrHasParent) {
+		t.Errorf("expected ErrHasParent; got: %v", err)
+	}
+	if err := f["uncle"].AddChild(nil); !errors.Is(err, ErrNilPerson) {
+		t.Errorf("expected ErrNilPerson; got: %v", err)
+	}
+}
+
+func TestMalformedTree(t *testing.T) {
+	a, b := New("a", ""), New("b", "")
+	a.children = []*Person{b}
+	b.children = []*Person{a}
+
+	if err := CheckTree(a); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle; got: %v", err)
+	}
+	if got := names(a.Descendants()); len(got) != 2 {
+		t.Errorf("traversal of a cycle must stop; got: %v", got)
+	}
+	if err := WriteDOT(&bytes.Buffer{}, a); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected WriteDOT to refuse a cycle; got: %v", err)
+	}
+
+	shared := New("shared", "")
+	root := New("root", "")
+	root.children = []*Person{shared, shared}
+	if err := CheckTree(root); err == nil || errors.Is(err, ErrCycle) {
+		t.Errorf("expected duplicate node error; got: %v", err)
+	}
+}
+
+func TestDeepTree(t *testing.T) {
+	root := New("root", "")
+	cur := root
+	// AddChild проверяет всю цепочку предков, поэтому длинную цепочку собираем напрямую
+	for i := 0; i < 200000; i++ {
+		next := &Person{name: "n", parent: cur}
+		cur.children = []*Person{next}
+		cur = next
+	}
+	if d := cur.Depth(); d != 200000 {
+		t.Errorf("expected depth 200000; got: %d", d)
+	}
+	if n := len(root.Descendants()); n != 200000 {
+		t.Errorf("expected 200000 descendants; got: %d", n)
+	}
+	if err := CheckTree(root); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriteDOTAndJSON(t *testing.T) {
+	f := family(t)
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, f["mom"]); err != nil {
+		t.Fatal(err)
+	}
+	expected := "digraph family {\n\tp0 [label=\"mom\"];\n\tp1 [label=\"alice\"];\n\tp2 [label=\"bob\"];\n\tp0 -> p1;\n\tp0 -> p2;\n}\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected DOT:\n%s", buf.String())
+	}
+
+	f["grandma"].SetSkills("cook")
+	f["grandma"].SetCapacity(3)
+	data, err := json.Marshal(f["grandma"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Person
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	carl := loaded.children[1].children[0]
+	if carl.name != "carl" || carl.Depth() != 2 || carl.Ancestors()[1] != &loaded {
+		t.Errorf("parent links must be restored after decoding: %s", data)
+	}
+	if !equal(loaded.Skills(), []string{"cook"}) || loaded.Capacity() != 3 {
+		t.Errorf("skills must survive JSON; got: %v, %d", loaded.Skills(), loaded.Capacity())
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHomeworkWorkflow(t *testing.T) {
+	clock := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	due := time.Date(2022, 10, 3, 23, 59, 0, 0, time.UTC)
+	rubric := []Criterion{{Name: "code", MaxPoints: 8}, {Name: "style", MaxPoints: 2}}
+	loops := Assignment{ID: "loops", Title: "Циклы", Due: due, Rubric: rubric}
+	maps := Assignment{ID: "maps", Title: "Мапы", Due: due, Rubric: rubric, LatePenalty: 0.1}
+
+	// автоматическая проверка: за код — по баллу за каждую строку, за стиль — 2, если нет табов
+	grader := GraderFunc(func(a Assignment, content string) (map[string]float64, string, error) {
+		scores := map[string]float64{"code": float64(strings.Count(content, "\n"))}
+		if !strings.Contains(content, "\t") {
+			scores["style"] = 2
+		}
+		return scores, "auto", nil
+	})
+
+	p := New("alice", "")
+	p.Submit(loops, "for {\n}\n")
+	clock = due.Add(30 * time.Hour) // опоздание на вторые сутки
+	p.Submit(maps, "m := map[int]int{}\n\tm[1] = 1\n")
+	if p.DoHomework() != "m := map[int]int{}\n\tm[1] = 1\n" {
+		t.Errorf("DoHomework must return the last submission; got: %q", p.DoHomework())
+	}
+
+	if err := p.GradePending(grader); err != nil {
+		t.Fatal(err)
+	}
+	subs := p.Submissions()
+	if g := subs[0].Grade; g.Final != 4 || g.Penalty != 0 || g.Max != 10 {
+		t.Errorf("unexpected on-time grade: %+v", g)
+	}
+	if g := subs[1].Grade; g.Raw != 2 || g.Penalty != 0.2 || math.Abs(g.Final-1.6) > 1e-9 {
+		t.Errorf("unexpected late grade: %+v", g)
+	}
+
+	report := p.Report()
+	if len(report.Entries) != 2 || math.Abs(report.Average-28) > 1e-9 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	expected := "alice: average 28.0%\n" +
+		"2022-10-01 12:00  loops        4.0/10.0   40.0%\n" +
+		"2022-10-05 05:59  maps         1.6/10.0   16.0%  late 30h0m0s\n"
+	if report.String() != expected {
+		t.Errorf("unexpected report text:\n%s", report.String())
+	}
+}
+
+func TestGraderValidation(t *testing.T) {
+	a := Assignment{ID: "hw", Rubric: []Criterion{{Name: "code", MaxPoints: 5}}}
+	s := &Submission{Assignment: a}
+	bad := []struct {
+		scores map[string]float64
+		err    error
+	}{
+		{map[string]float64{"tests": 1}, ErrUnknownCriterion},
+		{map[string]float64{"code": 6}, ErrScoreOutOfRange},
+		{map[string]float64{"code": -1}, ErrScoreOutOfRange},
+		{map[string]float64{"code": math.NaN()}, ErrScoreOutOfRange},
+		{map[string]float64{"code": math.Inf(1)}, ErrScoreOutOfRange},
+	}
+	for _, tt := range bad {
+		g := GraderFunc(func(Assignment, string) (map[string]float64, string, error) { return tt.scores, "", nil })
+		if _, err := Evaluate(s, g); !errors.Is(err, tt.err) {
+			t.Errorf("scores %v: expected %v; got: %v", tt.scores, tt.err, err)
+		}
+	}
+
+	failing := GraderFunc(func(Assignment, string) (map[string]float64, string, error) {
+		return nil, "", errors.New("checker is down")
+	})
+	p := New("bob", "")
+	p.Submit(a, "")
+	if err := p.GradePending(failing); err == nil || p.Submissions()[0].Grade != nil {
+		t.Errorf("failed grading must leave the submission ungraded; got: %v", err)
+	}
+}
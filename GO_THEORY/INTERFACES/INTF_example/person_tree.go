@@ -0,0 +1,175 @@
+package person
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrCycle — в семейном дереве есть цикл: человек оказался собственным предком
+	ErrCycle = errors.New("person: family tree has a cycle")
+	// ErrHasParent — у ребёнка уже есть родитель в дереве
+	ErrHasParent = errors.New("person: child already has a parent")
+	// ErrNilPerson — вместо человека передан nil
+	ErrNilPerson = errors.New("person: nil person")
+)
+
+// Обход дерева везде итеративный, с явным стеком, поэтому глубина дерева
+// ограничена только памятью, а не размером стека горутины.
+// Каждый человек посещается не больше одного раза, так что обход
+// некорректного дерева с циклом тоже завершается
+
+// AddChild — добавляет ребёнка и запоминает у него родителя.
+// Нельзя добавить nil, ребёнка, у которого уже есть родитель, и нельзя сделать человека потомком самого себя
+func (p *Person) AddChild(child *Person) error {
+	if child == nil {
+		return ErrNilPerson
+	}
+	if child.parent != nil {
+		return fmt.Errorf("%w: %s", ErrHasParent, child.name)
+	}
+	for a := p; a != nil; a = a.parent {
+		if a == child {
+			return fmt.Errorf("%w: %s would become own ancestor", ErrCycle, child.name)
+		}
+	}
+	child.parent = p
+	p.children = append(p.children, child)
+	return nil
+}
+
+// Parent — родитель в семейном дереве; nil, если неизвестен
+func (p Person) Parent() *Person {
+	return p.parent
+}
+
+// Descendants — все потомки в порядке обхода в глубину: ребёнок, затем его потомки, затем следующий ребёнок
+func (p Person) Descendants() []*Person {
+	var res []*Person
+	visited := map[*Person]bool{}
+	stack := reversed(p.children)
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == nil || visited[cur] {
+			continue
+		}
+		visited[cur] = true
+		res = append(res, cur)
+		stack = append(stack, reversed(cur.children)...)
+	}
+	return res
+}
+
+// Ancestors — родитель, родитель родителя и так далее до корня дерева
+func (p Person) Ancestors() []*Person {
+	var res []*Person
+	visited := map[*Person]bool{}
+	for a := p.parent; a != nil && !visited[a]; a = a.parent {
+		visited[a] = true
+		res = append(res, a)
+	}
+	return res
+}
+
+// Depth — номер поколения: у корня дерева 0, у его детей 1 и так далее
+func (p Person) Depth() int {
+	return len(p.Ancestors())
+}
+
+// LowestCommonAncestor — ближайший общий предок a и b. Если один из них предок другого,
+// он и будет ответом. nil, если a и b из разных деревьев
+func LowestCommonAncestor(a, b *Person) *Person {
+	lineage := map[*Person]bool{a: true}
+	for _, anc := range a.Ancestors() {
+		lineage[anc] = true
+	}
+	if lineage[b] {
+		return b
+	}
+	for _, anc := range b.Ancestors() {
+		if lineage[anc] {
+			return anc
+		}
+	}
+	return nil
+}
+
+// CheckTree — проверяет, что дерево потомков root корректно: нет циклов,
+// и ни один человек не встречается в дереве дважды
+func CheckTree(root *Person) error {
+	const (
+		inProgress = 1
+		done       = 2
+	)
+	type frame struct {
+		p    *Person
+		next int
+	}
+	state := map[*Person]int{root: inProgress}
+	stack := []frame{{p: root}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == len(top.p.children) {
+			state[top.p] = done
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		child := top.p.children[top.next]
+		top.next++
+		if child == nil {
+			continue
+		}
+		switch state[child] {
+		case inProgress:
+			return fmt.Errorf("%w: %s is a descendant of itself", ErrCycle, child.name)
+		case done:
+			return fmt.Errorf("person: %s appears in the tree twice", child.name)
+		}
+		state[child] = inProgress
+		stack = append(stack, frame{p: child})
+	}
+	return nil
+}
+
+// WriteDOT — выгружает дерево потомков root в формате Graphviz DOT:
+//
+//	dot -Tpng family.dot -o family.png
+func WriteDOT(w io.Writer, root *Person) error {
+	if err := CheckTree(root); err != nil {
+		return err
+	}
+	people := append([]*Person{root}, root.Descendants()...)
+	ids := make(map[*Person]int, len(people))
+	if _, err := fmt.Fprintln(w, "digraph family {"); err != nil {
+		return err
+	}
+	for i, p := range people {
+		ids[p] = i
+		if _, err := fmt.Fprintf(w, "\tp%d [label=%q];\n", i, p.name); err != nil {
+			return err
+		}
+	}
+	for _, p := range people {
+		for _, c := range p.children {
+			if c == nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "\tp%d -> p%d;\n", ids[p], ids[c]); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// reversed — копия слайса в обратном порядке, чтобы при снятии со стека дети шли по порядку
+func reversed(s []*Person) []*Person {
+	res := make([]*Person, len(s))
+	for i, p := range s {
+		res[len(s)-1-i] = p
+	}
+	return res
+}
@@ -1,12 +1,31 @@
 package robot
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+)
 
 // Robot — тип робота
 type Robot struct {
 	model       string
 	serialId    int
 	workCounter int
+	// maintenance — регламент обслуживания, rnd — источник случайных поломок
+	maintenance Maintenance
+	rnd         *rand.Rand
+	pricing     Pricing
+	// skills — что робот умеет, capacity — сколько задач он готов держать одновременно; 0 — без ограничения
+	skills   []string
+	capacity int
+	// state — исправен ли робот (stateOK, stateServiceDue, stateBroken); читается атомарно,
+	// чтобы компания могла проверять исправность, пока робот работает
+	state int32
+}
+
+// New — создаёт робота модели model с серийным номером serialId
+func New(model string, serialId int) *Robot {
+	return &Robot{model: model, serialId: serialId}
 }
 
 func (r Robot) String() string {
@@ -14,13 +33,98 @@ func (r Robot) String() string {
 }
 
 // Work — робот выполняет работы и запоминает количество выполненных задач. Поэтому получатель метода — по указателю
+// Если робот отказал, причина дописывается в конец отчёта; узнать её как ошибку можно через TryWork
 func (r *Robot) Work(tasks []string) string {
+	res, err := r.TryWork(tasks)
+	if err != nil {
+		res += "\n " + err.Error()
+	}
+	return res
+}
+
+// TryWork — как Work, но об отказе сообщает ошибкой ErrMaintenanceRequired или ErrBrokenDown.
+// Задачи до отказа считаются выполненными и попадают в отчёт
+func (r *Robot) TryWork(tasks []string) (string, error) {
 	res := fmt.Sprintf("%s work:", r)
 	for _, task := range tasks {
+		if err := r.check(); err != nil {
+			return res, err
+		}
 		res += "\n I do " + task
+		r.workCounter++
+		r.updateState()
 	}
-	r.workCounter += len(tasks)
-	return res
+	return res, nil
+}
+
+// Skills — что робот умеет; по навыкам компания решает, какие задачи ему можно поручить
+func (r *Robot) Skills() []string {
+	return append([]string(nil), r.skills...)
+}
+
+// SetSkills — задаёт навыки робота
+func (r *Robot) SetSkills(skills ...string) {
+	r.skills = append([]string(nil), skills...)
+}
+
+// Capacity — сколько задач робот готов держать одновременно; 0 — без ограничения
+func (r *Robot) Capacity() int {
+	return r.capacity
+}
+
+// SetCapacity — задаёт, сколько задач робот готов держать одновременно
+func (r *Robot) SetCapacity(n int) {
+	r.capacity = n
+}
+
+// robotJSON — представление Robot в JSON
+type robotJSON struct {
+	Model       string       `json:"model"`
+	SerialID    int          `json:"serial_id"`
+	WorkCounter int          `json:"work_counter"`
+	Maintenance *Maintenance `json:"maintenance,omitempty"`
+	Broken      bool         `json:"broken,omitempty"`
+	Pricing     *Pricing     `json:"pricing,omitempty"`
+	Skills      []string     `json:"skills,omitempty"`
+	Capacity    int          `json:"capacity,omitempty"`
+}
+
+// MarshalJSON — сохраняет модель, серийный номер и счётчик выполненных задач
+func (r Robot) MarshalJSON() ([]byte, error) {
+	v := robotJSON{
+		Model: r.model, SerialID: r.serialId, WorkCounter: r.workCounter, Broken: r.state == stateBroken,
+		Skills: r.skills, Capacity: r.capacity,
+	}
+	if r.maintenance != (Maintenance{}) {
+		m := r.maintenance
+		v.Maintenance = &m
+	}
+	if r.pricing != (Pricing{}) {
+		p := r.pricing
+		v.Pricing = &p
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON — восстанавливает робота, сохранённого через MarshalJSON
+func (r *Robot) UnmarshalJSON(data []byte) error {
+	var v robotJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.model, r.serialId, r.workCounter = v.Model, v.SerialID, v.WorkCounter
+	r.maintenance, r.rnd, r.state, r.pricing = Maintenance{}, nil, stateOK, Pricing{}
+	r.skills, r.capacity = v.Skills, v.Capacity
+	if v.Maintenance != nil {
+		r.SetMaintenance(*v.Maintenance)
+	}
+	if v.Pricing != nil {
+		r.pricing = *v.Pricing
+	}
+	if v.Broken {
+		r.state = stateBroken
+	}
+	return nil
 }
 
 // Важно
@@ -0,0 +1,81 @@
+package robot
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+)
+
+var (
+	// ErrMaintenanceRequired — робот выработал интервал обслуживания и отказывается работать до ремонта
+	ErrMaintenanceRequired = errors.New("robot: maintenance required")
+	// ErrBrokenDown — робот сломался и не будет работать до ремонта
+	ErrBrokenDown = errors.New("robot: broken down")
+)
+
+const (
+	stateOK int32 = iota
+	stateServiceDue
+	stateBroken
+)
+
+// Maintenance — регламент обслуживания робота
+type Maintenance struct {
+	// Interval — сколько задач робот выполняет от ремонта до ремонта; 0 — обслуживание не нужно
+	Interval int `json:"interval"`
+	// FailureRate — вероятность поломки на каждой задаче сверх интервала.
+	// Если 0, робот после интервала просто отказывается работать с ErrMaintenanceRequired
+	FailureRate float64 `json:"failure_rate,omitempty"`
+	// Seed — начальное значение генератора поломок; с одним и тем же Seed поломки повторяются
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// SetMaintenance — задаёт регламент обслуживания и перезапускает генератор поломок
+func (r *Robot) SetMaintenance(m Maintenance) {
+	r.maintenance = m
+	r.rnd = rand.New(rand.NewSource(m.Seed))
+	r.updateState()
+}
+
+// Available — готов ли робот к работе: не сломан и не ждёт обязательного обслуживания
+func (r *Robot) Available() bool {
+	return atomic.LoadInt32(&r.state) == stateOK
+}
+
+// Repair — ремонт и обслуживание: робот снова исправен, счётчик задач обнуляется
+func (r *Robot) Repair() {
+	r.workCounter = 0
+	atomic.StoreInt32(&r.state, stateOK)
+}
+
+// check — может ли робот взяться за очередную задачу
+func (r *Robot) check() error {
+	switch atomic.LoadInt32(&r.state) {
+	case stateBroken:
+		return fmt.Errorf("%s: %w", r, ErrBrokenDown)
+	case stateServiceDue:
+		return fmt.Errorf("%s: %w", r, ErrMaintenanceRequired)
+	}
+	m := r.maintenance
+	if m.Interval <= 0 || r.workCounter < m.Interval || m.FailureRate <= 0 {
+		return nil
+	}
+	if r.rnd == nil {
+		r.rnd = rand.New(rand.NewSource(m.Seed))
+	}
+	if r.rnd.Float64() < m.FailureRate {
+		atomic.StoreInt32(&r.state, stateBroken)
+		return fmt.Errorf("%s: %w", r, ErrBrokenDown)
+	}
+	return nil
+}
+
+// updateState — отмечает, что робот выработал интервал и без ремонта работать не будет.
+// Это делается сразу после задачи, чтобы компания перестала давать ему новые
+func (r *Robot) updateState() {
+	m := r.maintenance
+	if m.Interval > 0 && m.FailureRate <= 0 && r.workCounter >= m.Interval {
+		atomic.CompareAndSwapInt32(&r.state, stateOK, stateServiceDue)
+	}
+}
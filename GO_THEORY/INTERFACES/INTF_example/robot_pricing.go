@@ -0,0 +1,24 @@
+package robot
+
+// Pricing — во что обходится работа робота
+type Pricing struct {
+	// PerTask — плата за каждую выполненную задачу
+	PerTask float64 `json:"per_task"`
+	// Depreciation — износ за час работы
+	Depreciation float64 `json:"depreciation,omitempty"`
+}
+
+// SetPricing — задаёт стоимость работы робота
+func (r *Robot) SetPricing(p Pricing) {
+	r.pricing = p
+}
+
+// TaskRate — плата за выполненную задачу
+func (r *Robot) TaskRate() float64 {
+	return r.pricing.PerTask
+}
+
+// DepreciationRate — износ за час работы
+func (r *Robot) DepreciationRate() float64 {
+	return r.pricing.Depreciation
+}
@@ -0,0 +1,100 @@
+package robot
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestServiceInterval(t *testing.T) {
+	r := New("R2", 2)
+	r.SetMaintenance(Maintenance{Interval: 2})
+
+	if _, err := r.TryWork([]string{"a", "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Available() {
+		t.Error("robot must be unavailable once the interval is used up")
+	}
+	if _, err := r.TryWork([]string{"c"}); !errors.Is(err, ErrMaintenanceRequired) {
+		t.Errorf("expected ErrMaintenanceRequired; got: %v", err)
+	}
+
+	r.Repair()
+	if !r.Available() || r.workCounter != 0 {
+		t.Errorf("repair must reset the robot; counter %d", r.workCounter)
+	}
+	if _, err := r.TryWork([]string{"c"}); err != nil {
+		t.Errorf("unexpected error after repair: %v", err)
+	}
+}
+
+func TestSeededBreakdowns(t *testing.T) {
+	run := func() (done int, err error) {
+		r := New("C3", 3)
+		r.SetMaintenance(Maintenance{Interval: 5, FailureRate: 0.3, Seed: 42})
+		for i := 0; i < 100; i++ {
+			if _, err = r.TryWork([]string{"task"}); err != nil {
+				return done, err
+			}
+			done++
+		}
+		return done, nil
+	}
+
+	first, err := run()
+	if !errors.Is(err, ErrBrokenDown) {
+		t.Fatalf("expected ErrBrokenDown; got: %v", err)
+	}
+	if first < 5 {
+		t.Errorf("robot must not break within the service interval; broke after %d", first)
+	}
+	if second, _ := run(); second != first {
+		t.Errorf("breakdowns must be reproducible: %d and %d", first, second)
+	}
+}
+
+func TestWorkReportsFailure(t *testing.T) {
+	r := New("R2", 1)
+	r.SetMaintenance(Maintenance{Interval: 1})
+	if out := r.Work([]string{"a", "b"}); out != "Robot R2 serialID 1 work:\n I do a\n Robot R2 serialID 1: robot: maintenance required" {
+		t.Errorf("unexpected report: %q", out)
+	}
+}
+
+func TestRobotJSON(t *testing.T) {
+	r := New("R2", 7)
+	r.SetMaintenance(Maintenance{Interval: 1, FailureRate: 1, Seed: 3})
+	r.SetSkills("weld", "paint")
+	r.SetCapacity(2)
+	r.TryWork([]string{"a", "b"})
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Robot
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Available() || loaded.maintenance != r.maintenance || loaded.workCounter != 1 ||
+		len(loaded.Skills()) != 2 || loaded.Capacity() != 2 {
+		t.Errorf("unexpected robot after round trip: %s", data)
+	}
+}
+
+func TestPricing(t *testing.T) {
+	r := New("R2", 1)
+	r.SetPricing(Pricing{PerTask: 2, Depreciation: 0.5})
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Robot
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.TaskRate() != 2 || got.DepreciationRate() != 0.5 {
+		t.Errorf("expected pricing to survive JSON; got: %+v", got.pricing)
+	}
+}
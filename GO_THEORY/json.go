@@ -26,6 +26,10 @@ type (
 	Response struct {
 		Header ResponseHeader `json:"header"`
 		Data   ResponseData   `json:"data,omitempty"`
+		// Links — ссылки по имени, например self и next для постраничной выдачи
+		Links map[string]string `json:"links,omitempty"`
+		// Meta — произвольные сведения об ответе, например общее число элементов
+		Meta map[string]json.RawMessage `json:"meta,omitempty"`
 	}
 
 	ResponseHeader struct {
@@ -36,24 +40,52 @@ type (
 	ResponseData []ResponseDataItem
 
 	ResponseDataItem struct {
-		Type       string                `json:"type"`
-		Id         int                   `json:"id"`
+		Type       string                `json:"type" validate:"required"`
+		Id         int                   `json:"id" validate:"min=0"`
 		Attributes ResponseDataItemAttrs `json:"attributes"`
 	}
 
 	ResponseDataItemAttrs struct {
-		Email      string `json:"email"`
-		ArticleIds []int  `json:"article_ids"`
+		Email      string `json:"email" validate:"required,format=email"`
+		ArticleIds []int  `json:"article_ids" validate:"unique"`
 	}
-)
 
-func ReadResponse(rawResp string) (Response, error) {
-	resp := Response{}
-	if err := json.Unmarshal([]byte(rawResp), &resp); err != nil {
-		return Response{}, fmt.Errorf("JSON unmarshal: %w", err)
+	// ArticleAttrs — атрибуты ресурса с типом "article"
+	ArticleAttrs struct {
+		Title string `json:"title" validate:"required"`
 	}
+)
 
-	return resp, nil
+// rawDocument — тот же ответ в стиле JSON:API: статьи связаны с пользователем и приходят в included
+const rawDocument = `
+{
+    "header": {"code": 0},
+    "data": [{
+        "type": "user",
+        "id": 100,
+        "attributes": {"email": "bob@yandex.ru", "article_ids": [10, 11]},
+        "relationships": {
+            "articles": {"data": [{"type": "article", "id": 10}, {"type": "article", "id": 11}]}
+        }
+    }],
+    "included": [
+        {"type": "article", "id": 10, "attributes": {"title": "Интерфейсы в Go"}},
+        {"type": "article", "id": 11, "attributes": {"title": "Каналы"}}
+    ]
+}
+`
+
+// атрибуты ресурса теперь выбираются по его типу, см. ReadDocument
+func init() {
+	RegisterType("user", ResponseDataItemAttrs{})
+	RegisterType("article", ArticleAttrs{})
+}
+
+// ReadResponse — разбирает ответ. Ненулевой код в header — ошибка *HeaderError,
+// вид которой можно проверить через errors.Is, например errors.Is(err, ErrNotFound).
+// Данные проверяются по правилам из тегов validate, все нарушения возвращаются вместе в ValidationErrors
+func ReadResponse(rawResp string) (Response, error) {
+	return DecodeResponse(rawResp, DecodeOptions{})
 }
 
 func main() {
@@ -62,4 +94,14 @@ func main() {
 		panic(err)
 	}
 	fmt.Printf("%+v\n", resp)
+
+	doc, err := ReadDocument(rawDocument)
+	if err != nil {
+		panic(err)
+	}
+	user := doc.Data[0].Attributes.(*ResponseDataItemAttrs)
+	fmt.Println(user.Email)
+	for _, a := range doc.Data[0].Related("articles") {
+		fmt.Println(" ", a.Attributes.(*ArticleAttrs).Title)
+	}
 }
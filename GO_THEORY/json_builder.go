@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ResponseBuilder — собирает ответ на стороне сервера:
+//
+//	resp, err := NewResponse().
+//		Page("/users", 2, 10, total).
+//		Items(users...).
+//		Meta("generated_by", "api").
+//		Build()
+//
+// Ошибки в аргументах запоминаются, и Build возвращает первую из них
+type ResponseBuilder struct {
+	resp Response
+	err  error
+}
+
+// NewResponse — создаёт сборщик успешного ответа с кодом 0
+func NewResponse() *ResponseBuilder {
+	return &ResponseBuilder{}
+}
+
+// Code — задаёт код и сообщение в header
+func (b *ResponseBuilder) Code(code int, message string) *ResponseBuilder {
+	b.resp.Header = ResponseHeader{Code: code, Message: message}
+	return b
+}
+
+// Error — заполняет header по ошибке: *HeaderError переносится как есть, для зарегистрированной
+// через RegisterCode ошибки берётся её код, для остальных — 500. Сообщение — текст ошибки
+func (b *ResponseBuilder) Error(err error) *ResponseBuilder {
+	if err == nil {
+		return b.Code(0, "")
+	}
+	var he *HeaderError
+	if errors.As(err, &he) {
+		return b.Code(he.Code, he.Message)
+	}
+	return b.Code(codeOf(err), err.Error())
+}
+
+// codeOf — наименьший код, зарегистрированный для ошибки err, или 500
+func codeOf(err error) int {
+	codes.RLock()
+	defer codes.RUnlock()
+	best := 0
+	for code, sentinel := range codes.byCode {
+		if errors.Is(err, sentinel) && (best == 0 || code < best) {
+			best = code
+		}
+	}
+	if best == 0 {
+		return http.StatusInternalServerError
+	}
+	return best
+}
+
+// Items — добавляет элементы в data
+func (b *ResponseBuilder) Items(items ...ResponseDataItem) *ResponseBuilder {
+	b.resp.Data = append(b.resp.Data, items...)
+	return b
+}
+
+// Link — добавляет ссылку
+func (b *ResponseBuilder) Link(name, href string) *ResponseBuilder {
+	if b.resp.Links == nil {
+		b.resp.Links = make(map[string]string)
+	}
+	b.resp.Links[name] = href
+	return b
+}
+
+// Meta — добавляет сведения об ответе; value кодируется в JSON сразу
+func (b *ResponseBuilder) Meta(key string, value interface{}) *ResponseBuilder {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		b.fail(fmt.Errorf("meta %s: %w", key, err))
+		return b
+	}
+	if b.resp.Meta == nil {
+		b.resp.Meta = make(map[string]json.RawMessage)
+	}
+	b.resp.Meta[key] = raw
+	return b
+}
+
+// Page — описывает страницу page (с единицы) по perPage элементов из total:
+// ссылки self, first, last, а также prev и next, если такие страницы есть,
+// и сведения page, per_page, total и pages. Сами элементы страницы добавляются через Items
+func (b *ResponseBuilder) Page(baseURL string, page, perPage, total int) *ResponseBuilder {
+	if page < 1 || perPage < 1 || total < 0 {
+		b.fail(fmt.Errorf("invalid page %d of %d items by %d", page, total, perPage))
+		return b
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		b.fail(fmt.Errorf("page base URL: %w", err))
+		return b
+	}
+	pages := (total + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+	if page > pages {
+		b.fail(fmt.Errorf("page %d is out of range 1..%d", page, pages))
+		return b
+	}
+
+	link := func(name string, n int) {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(n))
+		q.Set("per_page", strconv.Itoa(perPage))
+		pu := *u
+		pu.RawQuery = q.Encode()
+		b.Link(name, pu.String())
+	}
+	link("self", page)
+	link("first", 1)
+	link("last", pages)
+	if page > 1 {
+		link("prev", page-1)
+	}
+	if page < pages {
+		link("next", page+1)
+	}
+	return b.Meta("page", page).Meta("per_page", perPage).Meta("total", total).Meta("pages", pages)
+}
+
+func (b *ResponseBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = fmt.Errorf("build response: %w", err)
+	}
+}
+
+// Build — готовый ответ. Данные проверяются по тем же правилам validate, что и в ReadResponse,
+// поэтому собранный ответ всегда читается обратно без ошибок разбора
+func (b *ResponseBuilder) Build() (Response, error) {
+	if b.err != nil {
+		return Response{}, b.err
+	}
+	if err := Validate(b.resp); err != nil {
+		return Response{}, fmt.Errorf("build response: %w", err)
+	}
+	return b.resp, nil
+}
+
+// HTTPStatus — HTTP-статус для кода из header: 200 для 0, сам код, если это статус ошибки HTTP, иначе 500
+func HTTPStatus(code int) int {
+	switch {
+	case code == 0:
+		return http.StatusOK
+	case code >= 400 && code <= 599:
+		return code
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// WriteResponse — отправляет ответ как application/json со статусом HTTPStatus(resp.Header.Code)
+func WriteResponse(w http.ResponseWriter, resp Response) error {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("JSON marshal: %w", err)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(HTTPStatus(resp.Header.Code))
+	_, err = w.Write(body)
+	return err
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func user(id int) ResponseDataItem {
+	return ResponseDataItem{Type: "user", Id: id, Attributes: ResponseDataItemAttrs{
+		Email:      fmt.Sprintf("user%d@example.com", id),
+		ArticleIds: []int{id, id + 1},
+	}}
+}
+
+func TestResponseBuilderRoundTrip(t *testing.T) {
+	resp, err := NewResponse().
+		Page("https://api.example.com/users?sort=id&q=<bob>", 2, 2, 5).
+		Items(user(3), user(4)).
+		Meta("generated_by", map[string]string{"service": "users"}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	links := map[string]string{
+		"self":  "https://api.example.com/users?page=2&per_page=2&q=%3Cbob%3E&sort=id",
+		"first": "https://api.example.com/users?page=1&per_page=2&q=%3Cbob%3E&sort=id",
+		"last":  "https://api.example.com/users?page=3&per_page=2&q=%3Cbob%3E&sort=id",
+		"prev":  "https://api.example.com/users?page=1&per_page=2&q=%3Cbob%3E&sort=id",
+		"next":  "https://api.example.com/users?page=3&per_page=2&q=%3Cbob%3E&sort=id",
+	}
+	if !reflect.DeepEqual(resp.Links, links) {
+		t.Errorf("expected %v; got: %v", links, resp.Links)
+	}
+	if string(resp.Meta["pages"]) != "3" || string(resp.Meta["total"]) != "5" {
+		t.Errorf("unexpected meta: %s", resp.Meta)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := WriteResponse(rec, resp); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("unexpected status %d and content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	got, err := DecodeResponse(rec.Body.String(), DecodeOptions{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, resp) {
+		t.Errorf("round trip changed the response:\nexpected %+v\ngot:     %+v", resp, got)
+	}
+}
+
+func TestResponseBuilderPages(t *testing.T) {
+	resp, err := NewResponse().Page("/users", 1, 10, 0).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := resp.Links["next"]; ok {
+		t.Errorf("single page must not link to the next one: %v", resp.Links)
+	}
+	if got, err := ReadResponse(mustMarshal(t, resp)); err != nil || !reflect.DeepEqual(got, resp) {
+		t.Errorf("empty page must round trip; got: %+v, %v", got, err)
+	}
+
+	for _, b := range []*ResponseBuilder{
+		NewResponse().Page("/users", 0, 10, 5),
+		NewResponse().Page("/users", 2, 10, 5),
+		NewResponse().Page("/users", 1, 0, 5),
+		NewResponse().Meta("bad", func() {}),
+		NewResponse().Items(ResponseDataItem{Id: -1}),
+	} {
+		if _, err := b.Build(); err == nil {
+			t.Error("expected build error")
+		}
+	}
+}
+
+func TestResponseBuilderErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   int
+		status int
+	}{
+		{ErrNotFound, 404, http.StatusNotFound},
+		{fmt.Errorf("load user: %w", ErrForbidden), 403, http.StatusForbidden},
+		{&HeaderError{Code: 1001, Message: "legacy"}, 1001, http.StatusInternalServerError},
+		{errors.New("boom"), 500, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		resp, err := NewResponse().Error(tt.err).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		if err := WriteResponse(rec, resp); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != tt.status {
+			t.Errorf("%v: expected status %d; got: %d", tt.err, tt.status, rec.Code)
+		}
+		var he *HeaderError
+		if _, err := ReadResponse(rec.Body.String()); !errors.As(err, &he) || he.Code != tt.code {
+			t.Errorf("%v: expected code %d; got: %v", tt.err, tt.code, err)
+		}
+		if sentinel := errors.Unwrap(tt.err); sentinel != nil && !errors.Is(he, sentinel) {
+			t.Errorf("%v: sentinel must survive the round trip; got: %v", tt.err, he)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
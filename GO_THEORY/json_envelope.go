@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Ответ в стиле JSON:API: элементы data бывают разных типов, и по полю type
+// видно, во что декодировать attributes. Связанные объекты приходят в included,
+// а в relationships остаются только ссылки на них вида {"type": "article", "id": 10}
+
+// types — зарегистрированные типы атрибутов по имени type
+var types = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+}{byName: make(map[string]reflect.Type)}
+
+// RegisterType — регистрирует тип атрибутов для ресурсов с полем type, равным name.
+// proto — пример значения, например ResponseDataItemAttrs{}; атрибуты декодируются в *T.
+// Повторная регистрация — ошибка программиста, поэтому RegisterType паникует
+func RegisterType(name string, proto interface{}) {
+	typ := reflect.TypeOf(proto)
+	if typ == nil {
+		panic(fmt.Sprintf("json: type %q: nil prototype", name))
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	types.Lock()
+	defer types.Unlock()
+	if _, ok := types.byName[name]; ok {
+		panic(fmt.Sprintf("json: type %q registered twice", name))
+	}
+	types.byName[name] = typ
+}
+
+type (
+	// Document — ответ с ресурсами разных типов
+	Document struct {
+		Header   ResponseHeader
+		Data     []*Resource
+		Included []*Resource
+	}
+
+	// Resource — ресурс с атрибутами своего типа.
+	// Attributes — указатель на зарегистрированный тип, например *ResponseDataItemAttrs,
+	// или json.RawMessage, если тип не зарегистрирован
+	Resource struct {
+		Type       string
+		Id         int
+		Attributes interface{}
+		// Relationships — связанные ресурсы по имени связи. Ресурс из data или included
+		// подставляется целиком; если его в ответе нет, остаётся заглушка с Type и Id и пустыми Attributes
+		Relationships map[string][]*Resource
+	}
+
+	// ResourceId — ссылка на ресурс
+	ResourceId struct {
+		Type string `json:"type"`
+		Id   int    `json:"id"`
+	}
+)
+
+// Related — связанные ресурсы по имени связи
+func (r *Resource) Related(name string) []*Resource {
+	return r.Relationships[name]
+}
+
+// Resolved — есть ли у ресурса атрибуты, то есть пришёл ли он в ответе, а не только ссылкой
+func (r *Resource) Resolved() bool {
+	return r.Attributes != nil
+}
+
+// представление документа на входе
+type (
+	documentJSON struct {
+		Header   ResponseHeader `json:"header"`
+		Data     []resourceJSON `json:"data,omitempty"`
+		Included []resourceJSON `json:"included,omitempty"`
+	}
+
+	resourceJSON struct {
+		Type          string                      `json:"type"`
+		Id            int                         `json:"id"`
+		Attributes    json.RawMessage             `json:"attributes,omitempty"`
+		Relationships map[string]relationshipJSON `json:"relationships,omitempty"`
+	}
+
+	// relationshipJSON — связь «к одному» ({"data": {...}} или {"data": null}) или «ко многим» ({"data": [...]})
+	relationshipJSON struct {
+		Data json.RawMessage `json:"data"`
+	}
+)
+
+// ReadDocument — декодирует ответ, выбирая тип атрибутов по полю type,
+// проверяет код в header и атрибуты так же, как ReadResponse, и связывает ресурсы из relationships с ресурсами из data и included
+func ReadDocument(rawResp string) (Document, error) {
+	var raw documentJSON
+	if err := json.Unmarshal([]byte(rawResp), &raw); err != nil {
+		return Document{}, fmt.Errorf("JSON unmarshal: %w", err)
+	}
+	if err := checkHeader(raw.Header); err != nil {
+		return Document{}, err
+	}
+
+	doc := Document{Header: raw.Header}
+	index := make(map[ResourceId]*Resource)
+	decode := func(section string, items []resourceJSON) ([]*Resource, error) {
+		res := make([]*Resource, len(items))
+		for i, item := range items {
+			attrs, err := decodeAttributes(item.Type, item.Attributes)
+			if err != nil {
+				return nil, fmt.Errorf("JSON unmarshal: %s[%d].attributes: %w", section, i, err)
+			}
+			if err := validateAt(attrs, fmt.Sprintf("%s[%d].attributes", section, i)); err != nil {
+				return nil, err
+			}
+			r := &Resource{Type: item.Type, Id: item.Id, Attributes: attrs}
+			// один и тот же ресурс может прийти и в data, и в included — ссылки ведут на первый
+			if _, ok := index[ResourceId{r.Type, r.Id}]; !ok {
+				index[ResourceId{r.Type, r.Id}] = r
+			}
+			res[i] = r
+		}
+		return res, nil
+	}
+
+	var err error
+	if doc.Data, err = decode("data", raw.Data); err != nil {
+		return Document{}, err
+	}
+	if doc.Included, err = decode("included", raw.Included); err != nil {
+		return Document{}, err
+	}
+	for i, item := range raw.Data {
+		if err := link(doc.Data[i], item.Relationships, index); err != nil {
+			return Document{}, fmt.Errorf("JSON unmarshal: data[%d].relationships: %w", i, err)
+		}
+	}
+	for i, item := range raw.Included {
+		if err := link(doc.Included[i], item.Relationships, index); err != nil {
+			return Document{}, fmt.Errorf("JSON unmarshal: included[%d].relationships: %w", i, err)
+		}
+	}
+	return doc, nil
+}
+
+// decodeAttributes — атрибуты в зарегистрированном для typ типе или как есть
+func decodeAttributes(typ string, raw json.RawMessage) (interface{}, error) {
+	types.RLock()
+	t, ok := types.byName[typ]
+	types.RUnlock()
+	if !ok {
+		if raw == nil {
+			raw = json.RawMessage("null")
+		}
+		return raw, nil
+	}
+	v := reflect.New(t)
+	if raw != nil {
+		if err := json.Unmarshal(raw, v.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return v.Interface(), nil
+}
+
+// link — заменяет ссылки из relationships на сами ресурсы
+func link(r *Resource, rels map[string]relationshipJSON, index map[ResourceId]*Resource) error {
+	for name, rel := range rels {
+		var ids []ResourceId
+		switch {
+		case len(rel.Data) == 0 || string(rel.Data) == "null":
+		case rel.Data[0] == '[':
+			if err := json.Unmarshal(rel.Data, &ids); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		default:
+			var id ResourceId
+			if err := json.Unmarshal(rel.Data, &id); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			ids = append(ids, id)
+		}
+
+		if r.Relationships == nil {
+			r.Relationships = make(map[string][]*Resource)
+		}
+		linked := make([]*Resource, len(ids))
+		for i, id := range ids {
+			target, ok := index[id]
+			if !ok {
+				target = &Resource{Type: id.Type, Id: id.Id}
+			}
+			linked[i] = target
+		}
+		r.Relationships[name] = linked
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReadDocument(t *testing.T) {
+	doc, err := ReadDocument(rawDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Data) != 1 || len(doc.Included) != 2 {
+		t.Fatalf("expected 1 data item and 2 included; got: %d and %d", len(doc.Data), len(doc.Included))
+	}
+	user, ok := doc.Data[0].Attributes.(*ResponseDataItemAttrs)
+	if !ok || user.Email != "bob@yandex.ru" {
+		t.Fatalf("expected user attributes; got: %#v", doc.Data[0].Attributes)
+	}
+	articles := doc.Data[0].Related("articles")
+	if len(articles) != 2 || articles[0] != doc.Included[0] {
+		t.Fatalf("relationship must point to the included resource; got: %+v", articles)
+	}
+	if title := articles[1].Attributes.(*ArticleAttrs).Title; title != "Каналы" {
+		t.Errorf("expected %q; got: %q", "Каналы", title)
+	}
+}
+
+func TestReadDocumentPolymorphic(t *testing.T) {
+	doc, err := ReadDocument(`{
+		"header": {"code": 0},
+		"data": [
+			{"type": "article", "id": 1, "attributes": {"title": "a"},
+			 "relationships": {
+				"author": {"data": {"type": "user", "id": 7}},
+				"editor": {"data": null},
+				"comments": {"data": [{"type": "comment", "id": 3}]}
+			 }},
+			{"type": "comment", "id": 3, "attributes": {"text": "hi"}}
+		],
+		"included": [{"type": "user", "id": 9, "attributes": {"email": "x@y.z"}}]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	article, comment := doc.Data[0], doc.Data[1]
+	if raw, ok := comment.Attributes.(json.RawMessage); !ok || string(raw) != `{"text": "hi"}` {
+		t.Errorf("unknown type must stay raw; got: %#v", comment.Attributes)
+	}
+
+	author := article.Related("author")
+	if len(author) != 1 || author[0].Id != 7 || author[0].Resolved() {
+		t.Errorf("missing resource must be an unresolved stub; got: %+v", author)
+	}
+	if editor, ok := article.Relationships["editor"]; !ok || len(editor) != 0 {
+		t.Errorf("null relationship must be empty; got: %+v", editor)
+	}
+	if comments := article.Related("comments"); len(comments) != 1 || comments[0] != comment {
+		t.Errorf("relationship must point to the resource in data; got: %+v", comments)
+	}
+}
+
+func TestReadDocumentErrors(t *testing.T) {
+	for _, raw := range []string{
+		`{"data": [`,
+		`{"data": [{"type": "user", "id": 1, "attributes": {"email": 5}}]}`,
+		`{"data": [{"type": "user", "id": 1, "relationships": {"x": {"data": "oops"}}}]}`,
+	} {
+		if _, err := ReadDocument(raw); err == nil {
+			t.Errorf("expected error for %s", raw)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Ошибки, которые сервер сообщает кодом в header. Сам код и текст сообщения
+// доступны через *HeaderError, а проверять вид ошибки удобно через errors.Is
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrInternal     = errors.New("internal error")
+)
+
+// Ошибки строгого режима
+var (
+	ErrUnknownField = errors.New("unknown field")
+	ErrDuplicateKey = errors.New("duplicate key")
+)
+
+// codes — виды ошибок по коду из header
+var codes = struct {
+	sync.RWMutex
+	byCode map[int]error
+}{byCode: map[int]error{
+	400: ErrBadRequest,
+	401: ErrUnauthorized,
+	403: ErrForbidden,
+	404: ErrNotFound,
+	500: ErrInternal,
+}}
+
+// RegisterCode — связывает код из header с ошибкой, которую можно проверить через errors.Is.
+// Код 0 означает успех, а повторная регистрация — ошибка программиста, поэтому в обоих случаях RegisterCode паникует
+func RegisterCode(code int, sentinel error) {
+	if code == 0 || sentinel == nil {
+		panic(fmt.Sprintf("json: cannot register code %d as %v", code, sentinel))
+	}
+	codes.Lock()
+	defer codes.Unlock()
+	if _, ok := codes.byCode[code]; ok {
+		panic(fmt.Sprintf("json: code %d registered twice", code))
+	}
+	codes.byCode[code] = sentinel
+}
+
+// HeaderError — ответ пришёл с ненулевым кодом в header.
+// Err — зарегистрированная для кода ошибка или nil, если код неизвестен
+type HeaderError struct {
+	Code    int
+	Message string
+	Err     error
+}
+
+func (e *HeaderError) Error() string {
+	s := fmt.Sprintf("response code %d", e.Code)
+	if e.Err != nil {
+		s += " (" + e.Err.Error() + ")"
+	}
+	if e.Message != "" {
+		s += ": " + e.Message
+	}
+	return s
+}
+
+func (e *HeaderError) Unwrap() error {
+	return e.Err
+}
+
+// checkHeader — ошибка для ненулевого кода в header
+func checkHeader(h ResponseHeader) error {
+	if h.Code == 0 {
+		return nil
+	}
+	codes.RLock()
+	sentinel := codes.byCode[h.Code]
+	codes.RUnlock()
+	return &HeaderError{Code: h.Code, Message: h.Message, Err: sentinel}
+}
+
+// DecodeOptions — настройки разбора ответа
+type DecodeOptions struct {
+	// Strict — отвергать неизвестные поля, повторяющиеся ключи и данные после ответа
+	Strict bool
+	// SkipValidation — не проверять данные по правилам из тегов validate
+	SkipValidation bool
+}
+
+// DecodeResponse — как ReadResponse, но с настройками разбора
+func DecodeResponse(rawResp string, opts DecodeOptions) (Response, error) {
+	resp := Response{}
+	if opts.Strict {
+		if err := strictUnmarshal([]byte(rawResp), &resp); err != nil {
+			return Response{}, fmt.Errorf("JSON unmarshal: %w", err)
+		}
+	} else if err := json.Unmarshal([]byte(rawResp), &resp); err != nil {
+		return Response{}, fmt.Errorf("JSON unmarshal: %w", err)
+	}
+
+	if err := checkHeader(resp.Header); err != nil {
+		return Response{}, err
+	}
+	if !opts.SkipValidation {
+		if err := Validate(resp); err != nil {
+			return Response{}, err
+		}
+	}
+	return resp, nil
+}
+
+// strictUnmarshal — json.Unmarshal, который не пропускает неизвестные поля,
+// повторяющиеся ключи и что-либо после первого значения
+func strictUnmarshal(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		// у encoding/json нет отдельного типа для этой ошибки, только текст
+		if field := strings.TrimPrefix(err.Error(), "json: unknown field "); field != err.Error() {
+			return fmt.Errorf("%w %s", ErrUnknownField, field)
+		}
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("unexpected data after top-level value")
+	}
+
+	// значение уже разобрано, значит JSON корректен и глубина вложенности ограничена
+	return checkDuplicates(json.NewDecoder(bytes.NewReader(data)), "")
+}
+
+// checkDuplicates — проверяет, что ни в одном объекте ключи не повторяются. Ключи сравниваются
+// без учёта регистра, как их сопоставляет с полями encoding/json: "id" и "ID" попадут в одно поле.
+// path — путь к текущему значению, например data[0].attributes
+func checkDuplicates(dec *json.Decoder, path string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		seen := make(map[string]bool)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string)
+			p := joinPath(path, key)
+			folded := strings.ToLower(key)
+			if seen[folded] {
+				return fmt.Errorf("%w %s", ErrDuplicateKey, p)
+			}
+			seen[folded] = true
+			if err := checkDuplicates(dec, p); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; dec.More(); i++ {
+			if err := checkDuplicates(dec, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	// закрывающая скобка
+	_, err = dec.Token()
+	return err
+}
+
+// joinPath — путь к полю key внутри значения path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadResponseHeaderErrors(t *testing.T) {
+	_, err := ReadResponse(`{"header": {"code": 404, "message": "user 100 not found"}}`)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound; got: %v", err)
+	}
+	var he *HeaderError
+	if !errors.As(err, &he) || he.Code != 404 || he.Message != "user 100 not found" {
+		t.Errorf("expected header error with code and message; got: %#v", err)
+	}
+
+	_, err = ReadResponse(`{"header": {"code": 999}}`)
+	if !errors.As(err, &he) || he.Err != nil || errors.Is(err, ErrInternal) {
+		t.Errorf("unknown code must give a plain header error; got: %v", err)
+	}
+
+	if _, err := ReadDocument(`{"header": {"code": 401}}`); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized from ReadDocument; got: %v", err)
+	}
+	if _, err := ReadResponse(rawResp); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRegisterCode(t *testing.T) {
+	errQuota := errors.New("quota exceeded")
+	RegisterCode(429, errQuota)
+	if _, err := ReadResponse(`{"header": {"code": 429}}`); !errors.Is(err, errQuota) {
+		t.Errorf("expected registered error; got: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate code")
+		}
+	}()
+	RegisterCode(404, errQuota)
+}
+
+func TestDecodeResponseStrict(t *testing.T) {
+	strict := DecodeOptions{Strict: true}
+	if _, err := DecodeResponse(rawResp, strict); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		raw      string
+		sentinel error
+		text     string
+	}{
+		{`{"header": {"code": 0, "extra": 1}}`, ErrUnknownField, `JSON unmarshal: unknown field "extra"`},
+		{`{"header": {"code": 0}, "data": [{"type": "user", "id": 1, "id": 2, "attributes": {"email": "a@b.c"}}]}`, ErrDuplicateKey, `JSON unmarshal: duplicate key data[0].id`},
+		{`{"header": {"code": 0}, "Header": {"code": 0}}`, ErrDuplicateKey, `JSON unmarshal: duplicate key Header`},
+		{`{"header": {"code": 0}, "data": [{"type": "user", "id": 1, "ID": 2, "attributes": {"email": "a@b.c"}}]}`, ErrDuplicateKey, `JSON unmarshal: duplicate key data[0].ID`},
+		{`{"header": {"code": 0}} {}`, nil, `JSON unmarshal: unexpected data after top-level value`},
+		{`{"header": {"code": 0}}}`, nil, `JSON unmarshal: unexpected data after top-level value`},
+	}
+	for _, tt := range tests {
+		_, err := DecodeResponse(tt.raw, strict)
+		if err == nil || err.Error() != tt.text {
+			t.Errorf("expected %q; got: %v", tt.text, err)
+		}
+		if tt.sentinel != nil && !errors.Is(err, tt.sentinel) {
+			t.Errorf("expected %v; got: %v", tt.sentinel, err)
+		}
+		if _, err := ReadResponse(tt.raw); err != nil && tt.sentinel != nil {
+			t.Errorf("lenient mode must accept %s; got: %v", tt.raw, err)
+		}
+	}
+}
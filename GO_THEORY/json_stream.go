@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ItemError — не удалось разобрать элемент data с номером Index
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("JSON unmarshal: data[%d]: %v", e.Index, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// StreamDecoder — читает ответ из io.Reader и выдаёт элементы data по одному,
+// не загружая весь ответ в память. Пользоваться им можно так же, как bufio.Scanner:
+//
+//	d := NewStreamDecoder(r, DecodeOptions{})
+//	for d.Next() {
+//		item := d.Item()
+//	}
+//	if err := d.Err(); err != nil {
+//
+// Если header идёт в ответе до data, ненулевой код прерывает чтение ещё до первого элемента.
+// Если после — элементы успеют прочитаться, а ошибка придёт из Err в конце
+type StreamDecoder struct {
+	dec    *json.Decoder
+	opts   DecodeOptions
+	header ResponseHeader
+	links  map[string]string
+	meta   map[string]json.RawMessage
+	// seen — прочитанные ключи верхнего уровня, чтобы в строгом режиме найти повторы
+	seen    map[string]bool
+	started bool
+	inData  bool
+	done    bool
+
+	item  ResponseDataItem
+	index int
+	err   error
+}
+
+// NewStreamDecoder — создаёт потоковый декодер ответа из r
+func NewStreamDecoder(r io.Reader, opts DecodeOptions) *StreamDecoder {
+	dec := json.NewDecoder(r)
+	if opts.Strict {
+		dec.DisallowUnknownFields()
+	}
+	return &StreamDecoder{dec: dec, opts: opts, seen: make(map[string]bool), index: -1}
+}
+
+// Next — переходит к следующему элементу data. Возвращает false, когда элементы кончились или произошла ошибка
+func (d *StreamDecoder) Next() bool {
+	for d.err == nil && !d.done {
+		if !d.inData {
+			d.err = d.advance()
+			continue
+		}
+		if !d.dec.More() {
+			// закрывающая скобка массива data
+			if _, err := d.dec.Token(); err != nil {
+				d.err = fmt.Errorf("JSON unmarshal: %w", err)
+			}
+			d.inData = false
+			continue
+		}
+
+		d.index++
+		d.item = ResponseDataItem{}
+		if err := d.decodeItem(&d.item); err != nil {
+			d.err = &ItemError{Index: d.index, Err: err}
+			return false
+		}
+		if !d.opts.SkipValidation {
+			if err := validateAt(d.item, fmt.Sprintf("data[%d]", d.index)); err != nil {
+				d.err = err
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// decodeItem — разбирает очередной элемент; в строгом режиме проверяет ещё и повторяющиеся ключи
+func (d *StreamDecoder) decodeItem(item *ResponseDataItem) error {
+	if !d.opts.Strict {
+		return d.dec.Decode(item)
+	}
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return err
+	}
+	return strictUnmarshal(raw, item)
+}
+
+// advance — читает ключи верхнего уровня до начала массива data или до конца ответа
+func (d *StreamDecoder) advance() error {
+	if !d.started {
+		d.started = true
+		if err := d.expect('{'); err != nil {
+			return err
+		}
+	}
+	for d.dec.More() {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return fmt.Errorf("JSON unmarshal: %w", err)
+		}
+		key := tok.(string)
+		if d.opts.Strict && d.seen[key] {
+			return fmt.Errorf("JSON unmarshal: %w %s", ErrDuplicateKey, key)
+		}
+		d.seen[key] = true
+
+		switch key {
+		case "header":
+			if err := d.dec.Decode(&d.header); err != nil {
+				return fmt.Errorf("JSON unmarshal: header: %w", err)
+			}
+			if err := checkHeader(d.header); err != nil {
+				return err
+			}
+		case "links":
+			if err := d.dec.Decode(&d.links); err != nil {
+				return fmt.Errorf("JSON unmarshal: links: %w", err)
+			}
+		case "meta":
+			if err := d.dec.Decode(&d.meta); err != nil {
+				return fmt.Errorf("JSON unmarshal: meta: %w", err)
+			}
+		case "data":
+			tok, err := d.dec.Token()
+			if err != nil {
+				return fmt.Errorf("JSON unmarshal: data: %w", err)
+			}
+			if tok == nil {
+				continue
+			}
+			if tok != json.Delim('[') {
+				return fmt.Errorf("JSON unmarshal: data: expected array, got %v", tok)
+			}
+			d.inData = true
+			return nil
+		default:
+			if d.opts.Strict {
+				return fmt.Errorf("JSON unmarshal: %w %q", ErrUnknownField, key)
+			}
+			var skip json.RawMessage
+			if err := d.dec.Decode(&skip); err != nil {
+				return fmt.Errorf("JSON unmarshal: %s: %w", key, err)
+			}
+		}
+	}
+	if err := d.expect('}'); err != nil {
+		return err
+	}
+	if d.opts.Strict {
+		if _, err := d.dec.Token(); err != io.EOF {
+			return errors.New("JSON unmarshal: unexpected data after top-level value")
+		}
+	}
+	d.done = true
+	return nil
+}
+
+func (d *StreamDecoder) expect(delim json.Delim) error {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return fmt.Errorf("JSON unmarshal: %w", err)
+	}
+	if tok != delim {
+		return fmt.Errorf("JSON unmarshal: expected %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+// Item — текущий элемент data
+func (d *StreamDecoder) Item() ResponseDataItem {
+	return d.item
+}
+
+// Index — номер текущего элемента в массиве data
+func (d *StreamDecoder) Index() int {
+	return d.index
+}
+
+// Header — header ответа, если он уже прочитан
+func (d *StreamDecoder) Header() ResponseHeader {
+	return d.header
+}
+
+// Links — ссылки из ответа, если они уже прочитаны
+func (d *StreamDecoder) Links() map[string]string {
+	return d.links
+}
+
+// Meta — сведения об ответе, если они уже прочитаны
+func (d *StreamDecoder) Meta() map[string]json.RawMessage {
+	return d.meta
+}
+
+// Err — ошибка, на которой остановилось чтение: *ItemError, если элемент data не разобрался,
+// ValidationErrors, если элемент нарушает правила validate (номер элемента есть в пути),
+// *HeaderError для ненулевого кода в header; nil, если ответ прочитан полностью
+func (d *StreamDecoder) Err() error {
+	return d.err
+}
+
+// StreamResponse — читает ответ из r и вызывает fn для каждого элемента data.
+// Если fn возвращает ошибку, чтение прекращается и StreamResponse возвращает её
+func StreamResponse(r io.Reader, opts DecodeOptions, fn func(index int, item ResponseDataItem) error) (ResponseHeader, error) {
+	d := NewStreamDecoder(r, opts)
+	for d.Next() {
+		if err := fn(d.Index(), d.Item()); err != nil {
+			return d.Header(), err
+		}
+	}
+	return d.Header(), d.Err()
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// writeLargeResponse — пишет в w ответ с n элементами data
+func writeLargeResponse(w io.Writer, n int) {
+	fmt.Fprint(w, `{"header": {"code": 0}, "data": [`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprintf(w, `{"type": "user", "id": %d, "attributes": {"email": "u%d@example.com", "article_ids": [%d]}}`, i, i, i)
+	}
+	fmt.Fprint(w, `]}`)
+}
+
+func TestStreamDecoderLarge(t *testing.T) {
+	const n = 100000
+	r, w := io.Pipe()
+	go func() {
+		writeLargeResponse(w, n)
+		w.Close()
+	}()
+
+	count := 0
+	header, err := StreamResponse(r, DecodeOptions{}, func(index int, item ResponseDataItem) error {
+		if item.Id != index || item.Attributes.ArticleIds[0] != index {
+			return fmt.Errorf("unexpected item %d: %+v", index, item)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != n || header.Code != 0 {
+		t.Errorf("expected %d items; got: %d", n, count)
+	}
+}
+
+func TestStreamDecoderMatchesReadResponse(t *testing.T) {
+	resp, err := ReadResponse(rawResp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewStreamDecoder(strings.NewReader(rawResp), DecodeOptions{Strict: true})
+	var items ResponseData
+	for d.Next() {
+		items = append(items, d.Item())
+	}
+	if err := d.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(items) != fmt.Sprint(resp.Data) {
+		t.Errorf("expected %v; got: %v", resp.Data, items)
+	}
+}
+
+func TestStreamDecoderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		opts  DecodeOptions
+		items int
+		check func(error) bool
+	}{
+		{"header first", `{"header": {"code": 404}, "data": [{"type": "user", "id": 1, "attributes": {"email": "a@b.c"}}]}`, DecodeOptions{}, 0,
+			func(err error) bool { return errors.Is(err, ErrNotFound) }},
+		{"header last", `{"data": [{"type": "user", "id": 1, "attributes": {"email": "a@b.c"}}, {"type": "user", "id": 2, "attributes": {"email": "a@b.c"}}], "header": {"code": 500}}`, DecodeOptions{}, 2,
+			func(err error) bool { return errors.Is(err, ErrInternal) }},
+		{"bad item", `{"data": [{"type": "user", "id": 1, "attributes": {"email": "a@b.c"}}, {"type": "user", "id": 2, "attributes": {"email": "a@b.c"}}, {"id": "three"}]}`, DecodeOptions{}, 2,
+			func(err error) bool {
+				var ie *ItemError
+				return errors.As(err, &ie) && ie.Index == 2 && strings.HasPrefix(err.Error(), "JSON unmarshal: data[2]: ")
+			}},
+		{"truncated", `{"data": [{"type": "user", "id": 1, "attributes": {"email": "a@b.c"}}, {"id"`, DecodeOptions{}, 1,
+			func(err error) bool { var ie *ItemError; return errors.As(err, &ie) && ie.Index == 1 }},
+		{"not an array", `{"data": {"type": "user", "id": 1, "attributes": {"email": "a@b.c"}}}`, DecodeOptions{}, 0,
+			func(err error) bool { return err != nil }},
+		{"unknown item field", `{"data": [{"id": 1, "name": "bob"}]}`, DecodeOptions{Strict: true}, 0,
+			func(err error) bool { return errors.Is(err, ErrUnknownField) }},
+		{"duplicate item key", `{"data": [{"id": 1, "id": 1}]}`, DecodeOptions{Strict: true}, 0,
+			func(err error) bool { return errors.Is(err, ErrDuplicateKey) }},
+		{"unknown top-level key", `{"extra": {}, "data": []}`, DecodeOptions{Strict: true}, 0,
+			func(err error) bool { return errors.Is(err, ErrUnknownField) }},
+	}
+	for _, tt := range tests {
+		d := NewStreamDecoder(strings.NewReader(tt.raw), tt.opts)
+		items := 0
+		for d.Next() {
+			items++
+		}
+		if items != tt.items {
+			t.Errorf("%s: expected %d items; got: %d", tt.name, tt.items, items)
+		}
+		if !tt.check(d.Err()) {
+			t.Errorf("%s: unexpected error: %v", tt.name, d.Err())
+		}
+	}
+
+	// неизвестные ключи и data: null в обычном режиме пропускаются
+	d := NewStreamDecoder(strings.NewReader(`{"meta": {"total": 0}, "data": null}`), DecodeOptions{})
+	if d.Next() || d.Err() != nil {
+		t.Errorf("expected empty stream; got error: %v", d.Err())
+	}
+	if string(d.Meta()["total"]) != "0" {
+		t.Errorf("expected meta to be kept; got: %s", d.Meta())
+	}
+}
+
+func TestStreamResponseCallbackError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	_, err := StreamResponse(strings.NewReader(`{"data": [{"type": "user", "id": 1, "attributes": {"email": "a@b.c"}}, {"type": "user", "id": 2, "attributes": {"email": "a@b.c"}}]}`), DecodeOptions{}, func(int, ResponseDataItem) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("expected to stop after the first item; got %d calls, error %v", calls, err)
+	}
+}
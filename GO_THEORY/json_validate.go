@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Правила проверки задаются в теге validate через запятую:
+//
+//	required      — значение не пустое: не ноль, не пустая строка, не пустой слайс
+//	format=email  — строка — адрес электронной почты; пустая строка пропускается, для неё есть required
+//	min=N         — число не меньше N, у строки или слайса не меньше N элементов
+//	unique        — в слайсе нет повторяющихся элементов
+//
+// Вложенные структуры, слайсы и указатели проверяются рекурсивно,
+// а путь к полю строится по именам из тега json, например data[0].attributes.email
+
+// FieldError — нарушенное правило для поля Path
+type FieldError struct {
+	Path    string
+	Rule    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors — все нарушения, найденные в значении
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "validation: " + strings.Join(msgs, "; ")
+}
+
+// Validate — проверяет v по правилам из тегов validate и возвращает ValidationErrors
+// со всеми нарушениями или nil
+func Validate(v interface{}) error {
+	return validateAt(v, "")
+}
+
+// validateAt — как Validate, но пути начинаются с path
+func validateAt(v interface{}, path string) error {
+	if errs := validateValue(reflect.ValueOf(v), path, nil); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateValue(v reflect.Value, path string, errs ValidationErrors) ValidationErrors {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return errs
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := jsonName(f)
+			if name == "-" {
+				continue
+			}
+			p := joinPath(path, name)
+			if tag, ok := f.Tag.Lookup("validate"); ok {
+				errs = checkRules(v.Field(i), p, tag, errs)
+			}
+			errs = validateValue(v.Field(i), p, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			errs = validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+	return errs
+}
+
+// jsonName — имя поля в JSON
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// checkRules — проверяет значение поля по правилам из тега.
+// Неизвестное или неприменимое к типу поля правило — ошибка программиста, поэтому checkRules паникует
+func checkRules(v reflect.Value, path, tag string, errs ValidationErrors) ValidationErrors {
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		var msg string
+		switch name {
+		case "required":
+			if isEmpty(v) {
+				msg = "is required"
+			}
+		case "format":
+			if arg != "email" {
+				panic(fmt.Sprintf("json: %s: unknown format %q", path, arg))
+			}
+			if s := v.String(); s != "" && !isEmail(s) {
+				msg = fmt.Sprintf("%q is not an email", s)
+			}
+		case "min":
+			msg = checkMin(v, path, arg)
+		case "unique":
+			msg = checkUnique(v, path)
+		default:
+			panic(fmt.Sprintf("json: %s: unknown validation rule %q", path, rule))
+		}
+		if msg != "" {
+			errs = append(errs, FieldError{Path: path, Rule: name, Message: msg})
+		}
+	}
+	return errs
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// isEmail — строка — это только адрес, без имени и угловых скобок
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+func checkMin(v reflect.Value, path, arg string) string {
+	min, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		panic(fmt.Sprintf("json: %s: invalid min %q", path, arg))
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if float64(v.Int()) < min {
+			return fmt.Sprintf("%d is less than %s", v.Int(), arg)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if float64(v.Uint()) < min {
+			return fmt.Sprintf("%d is less than %s", v.Uint(), arg)
+		}
+	case reflect.Float32, reflect.Float64:
+		if v.Float() < min {
+			return fmt.Sprintf("%g is less than %s", v.Float(), arg)
+		}
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		if float64(v.Len()) < min {
+			return fmt.Sprintf("length %d is less than %s", v.Len(), arg)
+		}
+	default:
+		panic(fmt.Sprintf("json: %s: min does not apply to %v", path, v.Type()))
+	}
+	return ""
+}
+
+func checkUnique(v reflect.Value, path string) string {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || !v.Type().Elem().Comparable() {
+		panic(fmt.Sprintf("json: %s: unique does not apply to %v", path, v.Type()))
+	}
+	seen := make(map[interface{}]int, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i).Interface()
+		if first, ok := seen[elem]; ok {
+			return fmt.Sprintf("%v at index %d repeats index %d", elem, i, first)
+		}
+		seen[elem] = i
+	}
+	return ""
+}
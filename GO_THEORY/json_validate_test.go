@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadResponseValidation(t *testing.T) {
+	_, err := ReadResponse(`{
+		"header": {"code": 0},
+		"data": [
+			{"type": "user", "id": 1, "attributes": {"email": "bob@yandex.ru", "article_ids": [1, 2]}},
+			{"type": "", "id": -5, "attributes": {"email": "bob at yandex", "article_ids": [3, 4, 3]}},
+			{"type": "user", "id": 2, "attributes": {}}
+		]
+	}`)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors; got: %v", err)
+	}
+	expected := []struct{ path, rule string }{
+		{"data[1].type", "required"},
+		{"data[1].id", "min"},
+		{"data[1].attributes.email", "format"},
+		{"data[1].attributes.article_ids", "unique"},
+		{"data[2].attributes.email", "required"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d violations; got: %v", len(expected), errs)
+	}
+	for i, e := range expected {
+		if errs[i].Path != e.path || errs[i].Rule != e.rule {
+			t.Errorf("expected %s %s; got: %s %s", e.path, e.rule, errs[i].Path, errs[i].Rule)
+		}
+	}
+	if !strings.Contains(err.Error(), `data[1].attributes.email: "bob at yandex" is not an email`) {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	if _, err := DecodeResponse(`{"data": [{"id": -1}]}`, DecodeOptions{SkipValidation: true}); err != nil {
+		t.Errorf("validation must be skipped; got: %v", err)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	type inner struct {
+		Tags []string `json:"tags" validate:"min=1,unique"`
+	}
+	type sample struct {
+		Name  string  `json:"name,omitempty" validate:"min=3"`
+		Score float64 `validate:"min=0.5"`
+		Inner []inner `json:"inner"`
+		Next  *inner  `json:"next"`
+		skip  int     `validate:"required"`
+	}
+	err := Validate(sample{
+		Name:  "ab",
+		Score: 0.1,
+		Inner: []inner{{Tags: []string{"a"}}, {Tags: []string{"b", "b"}}},
+		Next:  &inner{},
+	})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors; got: %v", err)
+	}
+	paths := make([]string, len(errs))
+	for i, e := range errs {
+		paths[i] = e.Path
+	}
+	if got, expected := strings.Join(paths, " "), "name Score inner[1].tags next.tags"; got != expected {
+		t.Errorf("expected %q; got: %q", expected, got)
+	}
+
+	if err := Validate(sample{Name: "abc", Score: 1}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on unknown rule")
+		}
+	}()
+	Validate(struct {
+		A int `validate:"positive"`
+	}{})
+}
+
+func TestStreamAndDocumentValidation(t *testing.T) {
+	d := NewStreamDecoder(strings.NewReader(`{"data": [
+		{"type": "user", "id": 1, "attributes": {"email": "a@b.c"}},
+		{"type": "user", "id": -1, "attributes": {"email": "a@b.c"}}
+	]}`), DecodeOptions{})
+	items := 0
+	for d.Next() {
+		items++
+	}
+	var errs ValidationErrors
+	if items != 1 || !errors.As(d.Err(), &errs) || errs[0].Path != "data[1].id" {
+		t.Errorf("expected violation at data[1].id after 1 item; got %d items, error %v", items, d.Err())
+	}
+
+	_, err := ReadDocument(`{"data": [], "included": [{"type": "article", "id": 1, "attributes": {}}]}`)
+	if !errors.As(err, &errs) || errs[0].Path != "included[0].attributes.title" {
+		t.Errorf("expected violation at included[0].attributes.title; got: %v", err)
+	}
+}
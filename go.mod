@@ -4,6 +4,8 @@ go 1.19
 
 replace arrint => /arrint
 
+require gopkg.in/yaml.v3 v3.0.1
+
 require (
 	github.com/yuin/goldmark v1.5.2 // indirect
 	golang.org/x/mod v0.6.0-dev.0.20220419223038-86c51ed26bb4 // indirect